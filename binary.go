@@ -0,0 +1,376 @@
+package set
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// Sets marshal to a version byte followed by the gob encoding of their
+// elements as a slice, in insertion order for ordered sets. Unmarshalling
+// replaces the contents of the set. Untyped sets carry their elements as
+// interface values, so any non-basic element types must be registered with
+// gob.Register on both ends. Map backed sets reject elements that cannot be
+// map keys with an ErrUnhashable. Sorted sets cannot send their comparator, so
+// they are not registered for interface-typed fields and only decode into
+// an existing set. The same goes for hash sets and their hash and equality.
+
+const binaryVersion byte = 1
+
+var (
+	_ encoding.BinaryMarshaler   = (*unsafeSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*unsafeSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*unsafeSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*unsafeSet[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*safeSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*safeSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*safeSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*safeSet[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*unsafeOrderedSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*unsafeOrderedSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*unsafeOrderedSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*unsafeOrderedSet[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*safeOrderedSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*safeOrderedSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*safeOrderedSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*safeOrderedSet[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*unsafeSortedSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*unsafeSortedSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*unsafeSortedSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*unsafeSortedSet[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*safeSortedSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*safeSortedSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*safeSortedSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*safeSortedSet[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*unsafeHashSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*unsafeHashSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*unsafeHashSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*unsafeHashSet[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*safeHashSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*safeHashSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*safeHashSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*safeHashSet[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*shardedSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*shardedSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*shardedSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*shardedSet[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*cowSet[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*cowSet[interface{}])(nil)
+	_ gob.GobEncoder             = (*cowSet[interface{}])(nil)
+	_ gob.GobDecoder             = (*cowSet[interface{}])(nil)
+)
+
+func init() {
+	RegisterGob[interface{}]()
+}
+
+// RegisterGob registers the set implementations holding elements of type T
+// with encoding/gob, so that TypedSet[T] values can travel in interface-typed
+// fields and arguments. The untyped implementations are always registered.
+func RegisterGob[T comparable]() {
+	gob.Register((*unsafeSet[T])(nil))
+	gob.Register((*safeSet[T])(nil))
+	gob.Register((*unsafeOrderedSet[T])(nil))
+	gob.Register((*safeOrderedSet[T])(nil))
+	gob.Register((*shardedSet[T])(nil))
+	gob.Register((*cowSet[T])(nil))
+}
+
+var errEmptyBinary = errors.New("set: no binary data to unmarshal")
+
+func marshalBinary[T any](elems []T) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(binaryVersion)
+	if err := gob.NewEncoder(&buf).Encode(elems); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalBinary[T any](b []byte) ([]T, error) {
+	if len(b) == 0 {
+		return nil, errEmptyBinary
+	}
+
+	if b[0] != binaryVersion {
+		return nil, fmt.Errorf("set: unsupported binary version %d", b[0])
+	}
+
+	var elems []T
+	if err := gob.NewDecoder(bytes.NewReader(b[1:])).Decode(&elems); err != nil {
+		return nil, err
+	}
+	return elems, nil
+}
+
+func (s *unsafeSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *unsafeSet[T]) UnmarshalBinary(b []byte) error {
+	elems, err := unmarshalBinary[T](b)
+	if err != nil {
+		return err
+	}
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	*s = *newUnsafeSet[T]()
+	s.Add(elems...)
+	return nil
+}
+
+func (s *unsafeSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *unsafeSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+func (s *safeSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *safeSet[T]) UnmarshalBinary(b []byte) error {
+	decoded := newUnsafeSet[T]()
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s != nil && s.s.frozen {
+		return ErrFrozen
+	}
+	s.s = decoded
+	return nil
+}
+
+func (s *safeSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *safeSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+func (s *unsafeOrderedSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *unsafeOrderedSet[T]) UnmarshalBinary(b []byte) error {
+	elems, err := unmarshalBinary[T](b)
+	if err != nil {
+		return err
+	}
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	*s = *newUnsafeOrderedSet[T]()
+	s.Add(elems...)
+	return nil
+}
+
+func (s *unsafeOrderedSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *unsafeOrderedSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+func (s *safeOrderedSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *safeOrderedSet[T]) UnmarshalBinary(b []byte) error {
+	decoded := newUnsafeOrderedSet[T]()
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s != nil && s.s.frozen {
+		return ErrFrozen
+	}
+	s.s = decoded
+	return nil
+}
+
+func (s *safeOrderedSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *safeOrderedSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+func (s *unsafeSortedSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *unsafeSortedSet[T]) UnmarshalBinary(b []byte) error {
+	elems, err := unmarshalBinary[T](b)
+	if err != nil {
+		return err
+	}
+
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	decoded := newUnsafeSortedSet(s.compare)
+	decoded.Add(elems...)
+	*s = *decoded
+	return nil
+}
+
+func (s *unsafeSortedSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *unsafeSortedSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+func (s *safeSortedSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *safeSortedSet[T]) UnmarshalBinary(b []byte) error {
+	s.RLock()
+	decoded := newUnsafeSortedSet(s.s.compare)
+	s.RUnlock()
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s.frozen {
+		return ErrFrozen
+	}
+	s.s = decoded
+	return nil
+}
+
+func (s *safeSortedSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *safeSortedSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+func (s *unsafeHashSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *unsafeHashSet[T]) UnmarshalBinary(b []byte) error {
+	elems, err := unmarshalBinary[T](b)
+	if err != nil {
+		return err
+	}
+
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	decoded := newUnsafeHashSet(s.hash, s.equal)
+	decoded.Add(elems...)
+	*s = *decoded
+	return nil
+}
+
+func (s *unsafeHashSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *unsafeHashSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+func (s *safeHashSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *safeHashSet[T]) UnmarshalBinary(b []byte) error {
+	s.RLock()
+	decoded := newUnsafeHashSet(s.s.hash, s.s.equal)
+	s.RUnlock()
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s.frozen {
+		return ErrFrozen
+	}
+	s.s = decoded
+	return nil
+}
+
+func (s *safeHashSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *safeHashSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+func (s *shardedSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *shardedSet[T]) UnmarshalBinary(b []byte) error {
+	elems, err := unmarshalBinary[T](b)
+	if err != nil {
+		return err
+	}
+
+	return s.replace(elems)
+}
+
+func (s *shardedSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *shardedSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
+
+func (s *cowSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice())
+}
+
+func (s *cowSet[T]) UnmarshalBinary(b []byte) error {
+	elems, err := unmarshalBinary[T](b)
+	if err != nil {
+		return err
+	}
+
+	return s.replace(elems)
+}
+
+func (s *cowSet[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *cowSet[T]) GobDecode(b []byte) error {
+	return s.UnmarshalBinary(b)
+}
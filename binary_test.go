@@ -0,0 +1,110 @@
+package set
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	for name, a := range constructors {
+		b, err := a(1, "two", 3.0).(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		if b[0] != binaryVersion {
+			t.Errorf("%v: expected version byte %v, got %v", name, binaryVersion, b[0])
+		}
+
+		s := a("stale")
+		if err := s.(encoding.BinaryUnmarshaler).UnmarshalBinary(b); err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		if !s.Equal(NewSet(1, "two", 3.0)) {
+			t.Errorf("%v: expected {1, two, 3}, got %v", name, s.ToSlice())
+		}
+	}
+}
+
+func TestMarshalBinaryEmpty(t *testing.T) {
+	for name, a := range constructors {
+		b, err := a().(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		s := a(1)
+		if err := s.(encoding.BinaryUnmarshaler).UnmarshalBinary(b); err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		if s.Len() != 0 {
+			t.Errorf("%v: expected an empty set, got %v", name, s.ToSlice())
+		}
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1).(encoding.BinaryUnmarshaler)
+
+		if err := s.UnmarshalBinary(nil); err == nil {
+			t.Errorf("%v: unmarshalling no data should fail", name)
+		}
+
+		if err := s.UnmarshalBinary([]byte{binaryVersion + 1}); err == nil {
+			t.Errorf("%v: unmarshalling an unknown version should fail", name)
+		}
+	}
+}
+
+func TestUnmarshalBinaryUnhashable(t *testing.T) {
+	b, err := marshalBinary([]interface{}{1, []int{2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Set", "OrderedSet", "UnsafeSet", "UnsafeOrderedSet", "ShardedSet", "CopyOnWriteSet"} {
+		s := constructors[name]("stale")
+		err := s.(encoding.BinaryUnmarshaler).UnmarshalBinary(b)
+		var unhashable ErrUnhashable
+		if !errors.As(err, &unhashable) {
+			t.Errorf("%v: expected ErrUnhashable, got %v", name, err)
+		}
+		if !s.Equal(NewSet("stale")) {
+			t.Errorf("%v: a failed unmarshal should leave the set unchanged, got %v", name, s)
+		}
+	}
+}
+
+func TestGobOrdered(t *testing.T) {
+	type payload struct {
+		Tags  Set
+		Queue TypedSet[string]
+	}
+
+	RegisterGob[string]()
+
+	var buf bytes.Buffer
+	in := payload{
+		Tags:  NewOrderedSet("c", "a", "b"),
+		Queue: NewTypedUnsafeOrderedSetFromSlice([]string{"z", "x", "y"}),
+	}
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	out := payload{Queue: NewTypedUnsafeOrderedSet[string]()}
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	assertOrder(t, out.Tags, "c", "a", "b")
+	if got := out.Queue.ToSlice(); len(got) != 3 || got[0] != "z" || got[1] != "x" || got[2] != "y" {
+		t.Errorf("expected [z x y], got %v", got)
+	}
+}
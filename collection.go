@@ -0,0 +1,48 @@
+package set
+
+// The thread safe sets answer the subset predicates about sets of other
+// kinds with these functions instead of holding their own lock while they
+// read other, which would deadlock against a set of that kind locking in the
+// opposite order. Each reads one set at a time and compares the sizes first,
+// so nothing is copied when the sizes settle the answer, and it stops at the
+// first element that does. Elements one set cannot look up, such as slices
+// given to a map backed set, are never its members.
+
+func isSubset[T any](s, other Collection[T]) bool {
+	return s.Len() <= other.Len() && contains(other, toSlice(s)...)
+}
+
+func isSuperset[T any](s, other Collection[T]) bool {
+	return s.Len() >= other.Len() && contains(s, toSlice(other)...)
+}
+
+func isProperSubset[T any](s, other Collection[T]) bool {
+	return s.Len() < other.Len() && contains(other, toSlice(s)...)
+}
+
+func isProperSuperset[T any](s, other Collection[T]) bool {
+	return s.Len() > other.Len() && contains(s, toSlice(other)...)
+}
+
+func isDisjoint[T any](s, other Collection[T]) bool {
+	if s.Len() == 0 || other.Len() == 0 {
+		return true
+	}
+
+	for _, elem := range toSlice(s) {
+		if contains(other, elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// toSlice copies the elements of c into a new slice.
+func toSlice[T any](c Collection[T]) []T {
+	elems := make([]T, 0, c.Len())
+	c.Each(func(elem T) bool {
+		elems = append(elems, elem)
+		return true
+	})
+	return elems
+}
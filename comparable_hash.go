@@ -0,0 +1,104 @@
+package set
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// hashComparable hashes v with seed so that values that are == hash the
+// same, like hash/maphash.Comparable, which needs a newer Go than this
+// module does. Common element types are hashed directly; everything else is
+// walked with reflect. It panics on values that cannot be map keys.
+func hashComparable[T comparable](seed maphash.Seed, v T) uint64 {
+	switch v := any(v).(type) {
+	case string:
+		return maphash.String(seed, v)
+	case int:
+		return hashUint64(seed, uint64(v))
+	case int64:
+		return hashUint64(seed, uint64(v))
+	case int32:
+		return hashUint64(seed, uint64(v))
+	case uint:
+		return hashUint64(seed, uint64(v))
+	case uint64:
+		return hashUint64(seed, v)
+	case uint32:
+		return hashUint64(seed, uint64(v))
+	}
+
+	var h maphash.Hash
+	h.SetSeed(seed)
+	writeHash(&h, reflect.ValueOf(&v).Elem())
+	return h.Sum64()
+}
+
+func hashUint64(seed maphash.Seed, v uint64) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	writeUint64(&h, v)
+	return h.Sum64()
+}
+
+func writeUint64(h *maphash.Hash, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	h.Write(b[:])
+}
+
+// writeFloat writes f so that 0 and -0, which are ==, hash the same.
+func writeFloat(h *maphash.Hash, f float64) {
+	if f == 0 {
+		f = 0
+	}
+	writeUint64(h, math.Float64bits(f))
+}
+
+// writeHash writes everything == looks at in v. Interface values only write
+// their dynamic value, since values of different types are never equal.
+func writeHash(h *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(h, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat(h, real(c))
+		writeFloat(h, imag(c))
+	case reflect.String:
+		h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(h, uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+			return
+		}
+		writeHash(h, v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeHash(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			// == ignores blank fields.
+			if v.Type().Field(i).Name != "_" {
+				writeHash(h, v.Field(i))
+			}
+		}
+	default:
+		panic(fmt.Sprintf("set: hash of unhashable type %v", v.Type()))
+	}
+}
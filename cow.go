@@ -0,0 +1,240 @@
+package set
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	cws *cowSet[interface{}]
+	_   Set = cws
+)
+
+// cowSet is a copy-on-write set for read-mostly workloads. Readers load the
+// current unsafeSet through an atomic pointer and never block. Writers take
+// mu, copy the current set, change the copy and publish it, so a published
+// unsafeSet is never modified again. Each call copies the set once however
+// many elements it changes, so writes should be batched: Add(a, b, c) rather
+// than three calls to Add.
+type cowSet[T comparable] struct {
+	p      atomic.Pointer[unsafeSet[T]]
+	mu     sync.Mutex
+	frozen atomic.Bool
+}
+
+func newCowSet[T comparable]() *cowSet[T] {
+	s := &cowSet[T]{}
+	s.p.Store(newUnsafeSet[T]())
+	return s
+}
+
+// load returns the current snapshot, which must not be modified. A zero
+// cowSet, such as one made by encoding/gob, reads as empty.
+func (s *cowSet[T]) load() *unsafeSet[T] {
+	if cur := s.p.Load(); cur != nil {
+		return cur
+	}
+	return newUnsafeSet[T]()
+}
+
+// update publishes a copy of the current snapshot changed by fn, or
+// returns ErrFrozen if s is frozen. Freeze takes mu, so nothing is
+// published after it returns.
+func (s *cowSet[T]) update(fn func(next *unsafeSet[T])) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.frozen.Load() {
+		return ErrFrozen
+	}
+
+	next := &unsafeSet[T]{m: maps.Clone(s.load().m)}
+	fn(next)
+	s.p.Store(next)
+	return nil
+}
+
+// mustUpdate is like update but panics with ErrFrozen.
+func (s *cowSet[T]) mustUpdate(fn func(next *unsafeSet[T])) {
+	if err := s.update(fn); err != nil {
+		panic(err)
+	}
+}
+
+func (s *cowSet[T]) Add(i ...T) {
+	if err := s.tryAdd(i); err != nil {
+		panic(err)
+	}
+}
+
+func (s *cowSet[T]) tryAdd(i []T) error {
+	if s.frozen.Load() {
+		return ErrFrozen
+	}
+	if s.load().Contains(i...) {
+		return nil
+	}
+
+	return s.update(func(next *unsafeSet[T]) {
+		next.Add(i...)
+	})
+}
+
+func (s *cowSet[T]) Contains(i ...T) bool {
+	return s.load().Contains(i...)
+}
+
+func (s *cowSet[T]) TryAdd(i ...T) error {
+	if err := checkHashable(i); err != nil {
+		return err
+	}
+	return s.tryAdd(i)
+}
+
+func (s *cowSet[T]) TryContains(i ...T) (bool, error) {
+	if err := checkHashable(i); err != nil {
+		return false, err
+	}
+	return s.Contains(i...), nil
+}
+
+func (s *cowSet[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkFrozen(s.frozen.Load())
+	s.p.Store(newUnsafeSet[T]())
+}
+
+func (s *cowSet[T]) Remove(i T) {
+	checkFrozen(s.frozen.Load())
+	if !s.load().Contains(i) {
+		return
+	}
+
+	s.mustUpdate(func(next *unsafeSet[T]) {
+		next.Remove(i)
+	})
+}
+
+func (s *cowSet[T]) Len() int {
+	return s.load().Len()
+}
+
+func (s *cowSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *cowSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
+}
+
+// Each walks the snapshot current when it is called, so unlike the locking
+// sets fn may modify the set.
+func (s *cowSet[T]) Each(fn func(elem T) bool) {
+	s.load().Each(fn)
+}
+
+func (s *cowSet[T]) Equal(other Collection[T]) bool {
+	return s.load().Equal(other)
+}
+
+// Clone shares the current snapshot, which neither set will modify.
+func (s *cowSet[T]) Clone() TypedSet[T] {
+	ret := &cowSet[T]{}
+	ret.p.Store(s.load())
+	return ret
+}
+
+func (s *cowSet[T]) ToSlice() []T {
+	return s.load().ToSlice()
+}
+
+func (s *cowSet[T]) RemoveFrom(other Collection[T]) {
+	elems := hashable(toSlice(other))
+	s.mustUpdate(func(next *unsafeSet[T]) {
+		for _, elem := range elems {
+			next.Remove(elem)
+		}
+	})
+}
+
+func (s *cowSet[T]) AddFrom(other Collection[T]) {
+	s.Add(toSlice(other)...)
+}
+
+func (s *cowSet[T]) RetainFrom(other Collection[T]) {
+	keep := newUnsafeSet[T]()
+	keep.Add(hashable(toSlice(other))...)
+	s.mustUpdate(func(next *unsafeSet[T]) {
+		next.RetainFrom(keep)
+	})
+}
+
+func (s *cowSet[T]) Union(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).Union)
+}
+
+func (s *cowSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).Intersect)
+}
+
+func (s *cowSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).Difference)
+}
+
+func (s *cowSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).SymmetricDifference)
+}
+
+// combine applies op to the current snapshot of s and to other, and
+// publishes the result as a new cowSet.
+func (s *cowSet[T]) combine(other Collection[T], op func(*unsafeSet[T], Collection[T]) TypedSet[T]) TypedSet[T] {
+	ret := &cowSet[T]{}
+	ret.p.Store(op(s.load(), other).(*unsafeSet[T]))
+	return ret
+}
+
+func (s *cowSet[T]) IsSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsSubset)
+}
+
+func (s *cowSet[T]) IsSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsSuperset)
+}
+
+func (s *cowSet[T]) IsProperSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsProperSubset)
+}
+
+func (s *cowSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsProperSuperset)
+}
+
+func (s *cowSet[T]) IsDisjoint(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsDisjoint)
+}
+
+// relate applies op to the current snapshot of s, which needs no lock, so
+// op reads other directly and stops as soon as it knows the answer.
+func (s *cowSet[T]) relate(other Collection[T], op func(*unsafeSet[T], Collection[T]) bool) bool {
+	return op(s.load(), other)
+}
+
+// replace publishes elems as the new contents of s. It returns an
+// ErrUnhashable, and changes nothing, if an element cannot be hashed.
+func (s *cowSet[T]) replace(elems []T) error {
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+
+	next := newUnsafeSet[T]()
+	next.Add(elems...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.frozen.Load() {
+		return ErrFrozen
+	}
+	s.p.Store(next)
+	return nil
+}
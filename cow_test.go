@@ -0,0 +1,144 @@
+package set
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func init() {
+	constructors["CopyOnWriteSet"] = func(s ...interface{}) Set {
+		return NewCopyOnWriteSet(s...)
+	}
+}
+
+func TestCopyOnWriteReadsDoNotBlock(t *testing.T) {
+	s := NewTypedCopyOnWriteSet(1, 2, 3)
+	cow := s.(*cowSet[int])
+
+	cow.mu.Lock()
+	defer cow.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.Contains(1)
+		s.Len()
+		s.ToSlice()
+		s.Equal(NewTypedSet(1, 2, 3))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reads blocked on a writer")
+	}
+}
+
+func TestCopyOnWriteSnapshots(t *testing.T) {
+	s := NewTypedCopyOnWriteSet(1, 2, 3)
+	it := s.Iterator()
+	c := s.Clone()
+
+	s.Add(4)
+	s.Remove(1)
+
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 3 {
+		t.Errorf("expected the iterator to see 3 elements, got %v", n)
+	}
+	if !c.Equal(NewTypedSet(1, 2, 3)) {
+		t.Errorf("expected the clone to keep {1, 2, 3}, got %v", c)
+	}
+
+	c.Add(5)
+	if s.Contains(5) || !s.Equal(NewTypedSet(2, 3, 4)) {
+		t.Errorf("expected {2, 3, 4}, got %v", s)
+	}
+}
+
+func TestCopyOnWriteEachCanWrite(t *testing.T) {
+	s := NewTypedCopyOnWriteSet(1, 2, 3)
+	s.Each(func(elem int) bool {
+		s.Add(elem * 10)
+		return true
+	})
+
+	if !s.Equal(NewTypedSet(1, 2, 3, 10, 20, 30)) {
+		t.Errorf("expected {1, 2, 3, 10, 20, 30}, got %v", s)
+	}
+}
+
+func TestCopyOnWriteSkipsNoOpWrites(t *testing.T) {
+	s := NewTypedCopyOnWriteSet(1, 2).(*cowSet[int])
+	before := s.p.Load()
+
+	s.Add(1, 2)
+	s.Remove(3)
+	if s.p.Load() != before {
+		t.Error("writes that change nothing should not copy the set")
+	}
+}
+
+func TestCopyOnWriteConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(4)
+
+	s := NewCopyOnWriteSet()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < N; i += 4 {
+				s.Add(i)
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < N; i++ {
+				if n := len(s.ToSlice()); n > N {
+					t.Errorf("snapshot with %v elements", n)
+				}
+				s.Contains(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Len() != N {
+		t.Errorf("expected %v elements, got %v", N, s.Len())
+	}
+}
+
+func BenchmarkReadMostlySet(b *testing.B) {
+	benchmarkReadMostly(b, NewSet())
+}
+
+func BenchmarkReadMostlyCopyOnWriteSet(b *testing.B) {
+	benchmarkReadMostly(b, NewCopyOnWriteSet())
+}
+
+// benchmarkReadMostly looks elements up from every goroutine while one
+// element in ten thousand is added.
+func benchmarkReadMostly(b *testing.B, s Set) {
+	for i := 0; i < N; i++ {
+		s.Add(i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%10000 == 0 {
+				s.Add(i)
+			} else {
+				s.Contains(i % N)
+			}
+			i++
+		}
+	})
+}
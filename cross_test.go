@@ -0,0 +1,426 @@
+package set
+
+import (
+	"fmt"
+	"testing"
+)
+
+var constructors = map[string]func(...interface{}) Set{
+	"Set":        NewSet,
+	"OrderedSet": NewOrderedSet,
+	"UnsafeSet": func(s ...interface{}) Set {
+		return NewUnsafeSetFromSlice(s)
+	},
+	"UnsafeOrderedSet": func(s ...interface{}) Set {
+		return NewUnsafeOrderedSetFromSlice(s)
+	},
+}
+
+func forEachPair(t *testing.T, fn func(t *testing.T, a, b func(...interface{}) Set)) {
+	for an, a := range constructors {
+		for bn, b := range constructors {
+			t.Run(an+"/"+bn, func(t *testing.T) {
+				fn(t, a, b)
+			})
+		}
+	}
+}
+
+func TestCrossEqual(t *testing.T) {
+	forEachPair(t, func(t *testing.T, a, b func(...interface{}) Set) {
+		if !a(1, 2, 3).Equal(b(3, 2, 1)) {
+			t.Error("sets with the same elements should be equal")
+		}
+
+		if a(1, 2, 3).Equal(b(1, 2, 4)) {
+			t.Error("sets with different elements should not be equal")
+		}
+
+		if a(1, 2).Equal(b(1, 2, 3)) {
+			t.Error("sets with different sizes should not be equal")
+		}
+	})
+}
+
+func TestCrossAddFrom(t *testing.T) {
+	forEachPair(t, func(t *testing.T, a, b func(...interface{}) Set) {
+		s := a(1, 2)
+		s.AddFrom(b(2, 3, 4))
+
+		if !s.Equal(a(1, 2, 3, 4)) {
+			t.Errorf("expected {1, 2, 3, 4}, got %v", s.ToSlice())
+		}
+	})
+}
+
+func TestCrossRemoveFrom(t *testing.T) {
+	forEachPair(t, func(t *testing.T, a, b func(...interface{}) Set) {
+		s := a(1, 2, 3)
+		s.RemoveFrom(b(2, 3, 4))
+
+		if !s.Equal(a(1)) {
+			t.Errorf("expected {1}, got %v", s.ToSlice())
+		}
+	})
+}
+
+func TestCrossRetainFrom(t *testing.T) {
+	forEachPair(t, func(t *testing.T, a, b func(...interface{}) Set) {
+		s := a(1, 2, 3)
+		s.RetainFrom(b(2, 3, 4))
+
+		if !s.Equal(a(2, 3)) {
+			t.Errorf("expected {2, 3}, got %v", s.ToSlice())
+		}
+	})
+}
+
+func TestOrderedAddFromKeepsOrder(t *testing.T) {
+	for _, a := range []Set{NewOrderedSet(1), NewUnsafeOrderedSet()} {
+		a.Add(1)
+		a.AddFrom(NewUnsafeOrderedSetFromSlice([]interface{}{5, 4, 3, 2}))
+
+		assertOrder(t, a, 1, 5, 4, 3, 2)
+	}
+}
+
+func TestCrossUnion(t *testing.T) {
+	forEachPair(t, func(t *testing.T, a, b func(...interface{}) Set) {
+		s, o := a(1, 2), b(2, 3)
+		u := s.Union(o)
+
+		if !u.Equal(a(1, 2, 3)) {
+			t.Errorf("expected {1, 2, 3}, got %v", u.ToSlice())
+		}
+
+		if s.Len() != 2 || o.Len() != 2 {
+			t.Error("Union should not modify its operands")
+		}
+	})
+}
+
+func TestCrossIntersect(t *testing.T) {
+	forEachPair(t, func(t *testing.T, a, b func(...interface{}) Set) {
+		s, o := a(1, 2, 3), b(2, 3, 4)
+		i := s.Intersect(o)
+
+		if !i.Equal(a(2, 3)) {
+			t.Errorf("expected {2, 3}, got %v", i.ToSlice())
+		}
+
+		if s.Len() != 3 || o.Len() != 3 {
+			t.Error("Intersect should not modify its operands")
+		}
+	})
+}
+
+func TestCrossDifference(t *testing.T) {
+	forEachPair(t, func(t *testing.T, a, b func(...interface{}) Set) {
+		s, o := a(1, 2, 3), b(2, 3, 4)
+		d := s.Difference(o)
+
+		if !d.Equal(a(1)) {
+			t.Errorf("expected {1}, got %v", d.ToSlice())
+		}
+
+		if s.Len() != 3 || o.Len() != 3 {
+			t.Error("Difference should not modify its operands")
+		}
+	})
+}
+
+func TestCrossSymmetricDifference(t *testing.T) {
+	forEachPair(t, func(t *testing.T, a, b func(...interface{}) Set) {
+		s, o := a(1, 2, 3), b(2, 3, 4)
+		d := s.SymmetricDifference(o)
+
+		if !d.Equal(a(1, 4)) {
+			t.Errorf("expected {1, 4}, got %v", d.ToSlice())
+		}
+
+		if s.Len() != 3 || o.Len() != 3 {
+			t.Error("SymmetricDifference should not modify its operands")
+		}
+	})
+}
+
+func TestAlgebraKeepsReceiverKind(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1)
+		want := fmt.Sprintf("%T", s)
+		for _, r := range []Set{
+			s.Union(NewSet(2)),
+			s.Intersect(NewOrderedSet(1)),
+			s.Difference(NewUnsafeSet()),
+			s.SymmetricDifference(NewUnsafeOrderedSet()),
+		} {
+			if got := fmt.Sprintf("%T", r); got != want {
+				t.Errorf("%v: expected result of type %v, got %v", name, want, got)
+			}
+		}
+	}
+}
+
+func TestOrderedAlgebraOrder(t *testing.T) {
+	for _, a := range []func(...interface{}) Set{constructors["OrderedSet"], constructors["UnsafeOrderedSet"]} {
+		s := a(5, 1, 4, 2)
+		o := NewUnsafeOrderedSetFromSlice([]interface{}{9, 2, 8, 5})
+
+		assertOrder(t, s.Union(o), 5, 1, 4, 2, 9, 8)
+		assertOrder(t, s.Intersect(o), 5, 2)
+		assertOrder(t, s.Difference(o), 1, 4)
+		assertOrder(t, s.SymmetricDifference(o), 1, 4, 9, 8)
+	}
+}
+
+func assertOrder(t *testing.T, s Set, want ...interface{}) {
+	t.Helper()
+	got := s.ToSlice()
+	if len(got) != len(want) {
+		t.Errorf("expected %v, got %v", want, got)
+		return
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+			return
+		}
+	}
+}
+
+func TestCrossSubsetPredicates(t *testing.T) {
+	forEachPair(t, func(t *testing.T, a, b func(...interface{}) Set) {
+		small, big, other := a(1, 2), b(1, 2, 3), b(4, 5)
+
+		if !small.IsSubset(big) || !small.IsProperSubset(big) {
+			t.Error("{1, 2} should be a proper subset of {1, 2, 3}")
+		}
+
+		if small.IsSuperset(big) || small.IsProperSuperset(big) {
+			t.Error("{1, 2} should not be a superset of {1, 2, 3}")
+		}
+
+		if !big.IsSuperset(a(1, 2)) || !big.IsProperSuperset(a(1, 2)) {
+			t.Error("{1, 2, 3} should be a proper superset of {1, 2}")
+		}
+
+		same := b(2, 1)
+		if !small.IsSubset(same) || !small.IsSuperset(same) {
+			t.Error("equal sets should be subsets and supersets of each other")
+		}
+
+		if small.IsProperSubset(same) || small.IsProperSuperset(same) {
+			t.Error("equal sets should not be proper subsets or supersets of each other")
+		}
+
+		if !a().IsSubset(other) || !a().IsDisjoint(other) {
+			t.Error("the empty set should be a subset of, and disjoint from, any set")
+		}
+
+		if !small.IsDisjoint(other) || small.IsDisjoint(big) {
+			t.Error("{1, 2} should be disjoint from {4, 5} and not from {1, 2, 3}")
+		}
+	})
+}
+
+func TestCrossSelfOperations(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+		s.AddFrom(s)
+		s.RetainFrom(s)
+		if !s.Equal(s) || !s.Equal(a(1, 2, 3)) {
+			t.Errorf("%v: expected {1, 2, 3}, got %v", name, s)
+		}
+
+		if !s.Union(s).Equal(s) || !s.Intersect(s).Equal(s) {
+			t.Errorf("%v: union and intersection with itself should be equal to the set", name)
+		}
+		if s.Difference(s).Len() != 0 || s.SymmetricDifference(s).Len() != 0 {
+			t.Errorf("%v: difference with itself should be empty", name)
+		}
+		if !s.IsSubset(s) || !s.IsSuperset(s) || s.IsProperSubset(s) || s.IsProperSuperset(s) || s.IsDisjoint(s) {
+			t.Errorf("%v: wrong predicates against itself", name)
+		}
+
+		s.RemoveFrom(s)
+		if s.Len() != 0 {
+			t.Errorf("%v: expected an empty set, got %v", name, s)
+		}
+	}
+}
+
+func countMembers(c Container[interface{}], l Lener, elems ...interface{}) (int, int) {
+	n := 0
+	for _, elem := range elems {
+		if c.Contains(elem) {
+			n++
+		}
+	}
+	return n, l.Len()
+}
+
+func TestCapabilities(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+		if n, l := countMembers(s, s, 1, 3, 5); n != 2 || l != 3 {
+			t.Errorf("%v: expected 2 of 3 members, got %v of %v", name, n, l)
+		}
+
+		var m Mutable[interface{}] = s
+		m.Add(4)
+		var it Iterable[interface{}] = s
+		n := 0
+		it.Each(func(interface{}) bool {
+			n++
+			return true
+		})
+		if n != 4 {
+			t.Errorf("%v: expected 4 elements, got %v", name, n)
+		}
+	}
+}
+
+// sliceCollection is a Collection that is not a TypedSet, like a set type
+// defined outside the package. It only has the methods Collection needs.
+type sliceCollection []interface{}
+
+func (c sliceCollection) Len() int {
+	return len(c)
+}
+
+func (c sliceCollection) Contains(i ...interface{}) bool {
+	for _, elem := range i {
+		found := false
+		for _, member := range c {
+			found = found || member == elem
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (c sliceCollection) Each(fn func(elem interface{}) bool) {
+	for _, elem := range c {
+		if !fn(elem) {
+			return
+		}
+	}
+}
+
+func TestCrossCollection(t *testing.T) {
+	for name, a := range constructors {
+		c := sliceCollection{2, 3, 4}
+		if !a(2, 3, 4).Equal(c) || a(1, 2, 3).Equal(c) {
+			t.Errorf("%v: Equal should compare against a Collection", name)
+		}
+		if !a(1, 2, 3).Equal(AsReadOnly(a(1, 2, 3))) {
+			t.Errorf("%v: Equal should accept a read-only view", name)
+		}
+
+		if u := a(1, 2).Union(c); !u.Equal(a(1, 2, 3, 4)) {
+			t.Errorf("%v: expected {1, 2, 3, 4}, got %v", name, u)
+		}
+		if i := a(1, 2).Intersect(c); !i.Equal(a(2)) {
+			t.Errorf("%v: expected {2}, got %v", name, i)
+		}
+		if d := a(1, 2).Difference(c); !d.Equal(a(1)) {
+			t.Errorf("%v: expected {1}, got %v", name, d)
+		}
+		if d := a(1, 2).SymmetricDifference(c); !d.Equal(a(1, 3, 4)) {
+			t.Errorf("%v: expected {1, 3, 4}, got %v", name, d)
+		}
+		if !a(2, 3).IsProperSubset(c) || !a(2, 3, 4, 5).IsSuperset(c) || !a(1).IsDisjoint(c) {
+			t.Errorf("%v: wrong predicates against a Collection", name)
+		}
+
+		s := a(1, 2)
+		s.AddFrom(c)
+		s.RemoveFrom(sliceCollection{4})
+		s.RetainFrom(sliceCollection{1, 3, 5})
+		if !s.Equal(a(1, 3)) {
+			t.Errorf("%v: expected {1, 3}, got %v", name, s)
+		}
+	}
+}
+
+// sizeOnly is a Collection of n elements that panics if anything reads them.
+type sizeOnly struct {
+	Collection[interface{}]
+	n int
+}
+
+func (c sizeOnly) Len() int {
+	return c.n
+}
+
+func TestCrossPredicatesCheckSizesFirst(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2)
+		if s.IsSubset(sizeOnly{n: 1}) || s.IsProperSubset(sizeOnly{n: 2}) {
+			t.Errorf("%v: a set should not be a subset of a smaller set", name)
+		}
+		if s.IsSuperset(sizeOnly{n: 3}) || s.IsProperSuperset(sizeOnly{n: 2}) {
+			t.Errorf("%v: a set should not be a superset of a bigger set", name)
+		}
+	}
+}
+
+func TestCrossUnhashable(t *testing.T) {
+	for an, a := range constructors {
+		for _, bn := range []string{"HashSet", "UnsafeHashSet"} {
+			b := constructors[bn]
+			t.Run(an+"/"+bn, func(t *testing.T) {
+				other := func() Set { return b(1, 3, []int{1}) }
+
+				if i := a(1, 2).Intersect(other()); !i.Equal(a(1)) {
+					t.Errorf("expected {1}, got %v", i)
+				}
+				if d := a(1, 2).Difference(other()); !d.Equal(a(2)) {
+					t.Errorf("expected {2}, got %v", d)
+				}
+				if a(1, 2, 3).Equal(other()) || a(1, 2, 3).IsSuperset(other()) || a(1, 2, 3, 4).IsProperSuperset(other()) {
+					t.Error("a set without the slice should not be equal to or a superset of one with it")
+				}
+				if !a(1).IsProperSubset(other()) || !a(2).IsDisjoint(other()) {
+					t.Error("wrong predicates against a set holding a slice")
+				}
+
+				s := a(1, 2)
+				s.RemoveFrom(other())
+				if !s.Equal(a(2)) {
+					t.Errorf("expected {2}, got %v", s)
+				}
+				s = a(1, 2)
+				s.RetainFrom(other())
+				if !s.Equal(a(1)) {
+					t.Errorf("expected {1}, got %v", s)
+				}
+
+				h := func() Set { return b(1, []int{1}) }
+				if i := h().Intersect(a(1, 2)); !i.Equal(b(1)) {
+					t.Errorf("expected {1}, got %v", i)
+				}
+				if d := h().Difference(a(1, 2)); !d.Equal(b([]int{1})) {
+					t.Errorf("expected {[1]}, got %v", d)
+				}
+				if h().Equal(a(1, 2)) || h().IsSubset(a(1, 2)) || !h().IsDisjoint(a(2)) {
+					t.Error("wrong predicates of a set holding a slice")
+				}
+
+				s = h()
+				s.RemoveFrom(a(1))
+				if !s.Equal(b([]int{1})) {
+					t.Errorf("expected {[1]}, got %v", s)
+				}
+				s = h()
+				s.RetainFrom(a(1))
+				if !s.Equal(b(1)) {
+					t.Errorf("expected {1}, got %v", s)
+				}
+			})
+		}
+	}
+}
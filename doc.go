@@ -0,0 +1,28 @@
+// Package set provides unordered, ordered, sorted and hash sets, each in a
+// thread safe and a thread unsafe variant, behind the Set family of
+// interfaces.
+//
+// # Compatibility
+//
+// The package is the module github.com/jtejido/set and follows semantic
+// versioning. Within a major version:
+//
+//   - Exported functions and types keep working as documented.
+//   - The capability interfaces Lener, Container, Iterable, Mutable and
+//     Freezer never change once released. New behaviour arrives as a new
+//     capability interface instead.
+//   - TypedSet, TypedOrderedSet and TypedSortedSet, and so Set, OrderedSet and
+//     SortedSet, may gain methods in minor releases by embedding new
+//     capability interfaces.
+//
+// Operations that take another set, such as Equal, AddFrom and Union, accept
+// a Collection, which only combines Lener, Container and Iterable and so
+// never changes either. Code outside the package with set types of its own
+// should implement Collection, which takes just Len, Contains and Each,
+// rather than Set: its sets then work with every operation in the package
+// and keep compiling as Set grows. Code that consumes sets should likewise
+// accept the smallest interface it needs, such as a Container for membership
+// checks. Every implementation in the package asserts at compile time that
+// it satisfies the interfaces, so a method added to an interface cannot be
+// forgotten by one of them.
+package set
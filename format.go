@@ -0,0 +1,240 @@
+package set
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// Sets print as Set{1, 2, 3} or OrderedSet{a, b}, applying the verb and
+// flags to every element. Unordered sets print their elements sorted: in
+// natural order when they are all integers, all floats or all strings, and
+// by their printed form otherwise. %#v prints a constructor call that
+// rebuilds the set, such as set.NewSetFromSlice([]interface {}{1, 2}).
+// Sorted sets print in sort order, and their %#v form refers to the
+// comparator as compare. Hash sets print like other unordered sets and refer
+// to their functions as hash and equal. Sharded sets print like other
+// unordered sets and pass their shard count in their %#v form.
+
+var (
+	_ fmt.Formatter = (*unsafeSet[interface{}])(nil)
+	_ fmt.Formatter = (*safeSet[interface{}])(nil)
+	_ fmt.Formatter = (*unsafeOrderedSet[interface{}])(nil)
+	_ fmt.Formatter = (*safeOrderedSet[interface{}])(nil)
+	_ fmt.Formatter = (*unsafeSortedSet[interface{}])(nil)
+	_ fmt.Formatter = (*safeSortedSet[interface{}])(nil)
+	_ fmt.Formatter = (*unsafeHashSet[interface{}])(nil)
+	_ fmt.Formatter = (*safeHashSet[interface{}])(nil)
+	_ fmt.Formatter = (*shardedSet[interface{}])(nil)
+	_ fmt.Formatter = (*cowSet[interface{}])(nil)
+	_ fmt.Formatter = (*immutableSet[interface{}])(nil)
+)
+
+func (s *unsafeSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *unsafeSet[T]) Format(f fmt.State, verb rune) {
+	elems := s.ToSlice()
+	sortElems(elems)
+	formatSet(f, verb, "Set", "UnsafeSet", "", elems)
+}
+
+func (s *safeSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *safeSet[T]) Format(f fmt.State, verb rune) {
+	s.RLock()
+	defer s.RUnlock()
+	elems := s.s.ToSlice()
+	sortElems(elems)
+	formatSet(f, verb, "Set", "Set", "", elems)
+}
+
+func (s *unsafeOrderedSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *unsafeOrderedSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, "OrderedSet", "UnsafeOrderedSet", "", s.ToSlice())
+}
+
+func (s *safeOrderedSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *safeOrderedSet[T]) Format(f fmt.State, verb rune) {
+	s.RLock()
+	defer s.RUnlock()
+	formatSet(f, verb, "OrderedSet", "OrderedSet", "", s.s.ToSlice())
+}
+
+func (s *unsafeSortedSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *unsafeSortedSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, "SortedSet", sortedConstructor[T]("UnsafeSortedSet"), "compare, ", s.ToSlice())
+}
+
+func (s *safeSortedSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *safeSortedSet[T]) Format(f fmt.State, verb rune) {
+	s.RLock()
+	defer s.RUnlock()
+	formatSet(f, verb, "SortedSet", sortedConstructor[T]("SortedSet"), "compare, ", s.s.ToSlice())
+}
+
+func (s *unsafeHashSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *unsafeHashSet[T]) Format(f fmt.State, verb rune) {
+	elems := s.ToSlice()
+	sortElems(elems)
+	formatSet(f, verb, "Set", "UnsafeHashSet", "hash, equal, ", elems)
+}
+
+func (s *safeHashSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *safeHashSet[T]) Format(f fmt.State, verb rune) {
+	s.RLock()
+	defer s.RUnlock()
+	elems := s.s.ToSlice()
+	sortElems(elems)
+	formatSet(f, verb, "Set", "HashSet", "hash, equal, ", elems)
+}
+
+func (s *shardedSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *shardedSet[T]) Format(f fmt.State, verb rune) {
+	elems := s.ToSlice()
+	sortElems(elems)
+	formatSet(f, verb, "Set", "ShardedSet", fmt.Sprintf("%d, ", len(s.shards)), elems)
+}
+
+func (s *cowSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *cowSet[T]) Format(f fmt.State, verb rune) {
+	elems := s.ToSlice()
+	sortElems(elems)
+	formatSet(f, verb, "Set", "CopyOnWriteSet", "", elems)
+}
+
+func (s *immutableSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+func (s *immutableSet[T]) Format(f fmt.State, verb rune) {
+	elems := s.ToSlice()
+	sortElems(elems)
+	formatSet(f, verb, "ImmutableSet", "ImmutableSet", "", elems)
+}
+
+// sortedConstructor names the constructor %#v prints for a sorted set. The
+// typed ones taking a comparator end in Func, since the typed names without
+// it take cmp.Ordered elements and no comparator.
+func sortedConstructor[T any](name string) string {
+	if _, untyped := any((*T)(nil)).(*interface{}); untyped {
+		return name
+	}
+	return name + "Func"
+}
+
+// formatSet writes elems as name{e1, e2}, or as a call to the FromSlice
+// form of constructor, passing args before elems, for %#v.
+func formatSet[T any](f fmt.State, verb rune, name, constructor, args string, elems []T) {
+	if verb == 'v' && f.Flag('#') {
+		if _, untyped := any((*T)(nil)).(*interface{}); !untyped {
+			constructor = "Typed" + constructor
+		}
+		fmt.Fprintf(f, "set.New%sFromSlice(%s%#v)", constructor, args, elems)
+		return
+	}
+
+	directive := fmt.FormatString(f, verb)
+	io.WriteString(f, name+"{")
+	for i, elem := range elems {
+		if i > 0 {
+			io.WriteString(f, ", ")
+		}
+		fmt.Fprintf(f, directive, elem)
+	}
+	io.WriteString(f, "}")
+}
+
+const (
+	unsortable = iota
+	sortInt
+	sortUint
+	sortFloat
+	sortString
+)
+
+func sortClass(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return sortInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return sortUint
+	case reflect.Float32, reflect.Float64:
+		return sortFloat
+	case reflect.String:
+		return sortString
+	}
+	return unsortable
+}
+
+// sortElems puts elems in a deterministic order for printing.
+func sortElems[T any](elems []T) {
+	values := make([]reflect.Value, len(elems))
+	class := unsortable
+	for i, elem := range elems {
+		values[i] = reflect.ValueOf(any(elem))
+		if c := sortClass(values[i]); i == 0 {
+			class = c
+		} else if c != class {
+			class = unsortable
+		}
+	}
+
+	var less func(i, j int) bool
+	switch class {
+	case sortInt:
+		less = func(i, j int) bool { return values[i].Int() < values[j].Int() }
+	case sortUint:
+		less = func(i, j int) bool { return values[i].Uint() < values[j].Uint() }
+	case sortFloat:
+		less = func(i, j int) bool { return values[i].Float() < values[j].Float() }
+	case sortString:
+		less = func(i, j int) bool { return values[i].String() < values[j].String() }
+	default:
+		keys := make([]string, len(elems))
+		for i, elem := range elems {
+			keys[i] = fmt.Sprintf("%v\x00%T", elem, elem)
+		}
+		less = func(i, j int) bool { return keys[i] < keys[j] }
+	}
+
+	order := make([]int, len(elems))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return less(order[a], order[b]) })
+
+	sorted := make([]T, len(elems))
+	for i, j := range order {
+		sorted[i] = elems[j]
+	}
+	copy(elems, sorted)
+}
@@ -0,0 +1,47 @@
+package set
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	for _, tt := range []struct {
+		s    fmt.Stringer
+		want string
+	}{
+		{NewSet(3, 1, 2).(fmt.Stringer), "Set{1, 2, 3}"},
+		{NewUnsafeSetFromSlice([]interface{}{"b", "c", "a"}).(fmt.Stringer), "Set{a, b, c}"},
+		{NewUnsafeSetFromSlice([]interface{}{2.5, -1.0}).(fmt.Stringer), "Set{-1, 2.5}"},
+		{NewSet("b", 1, "a").(fmt.Stringer), "Set{1, a, b}"},
+		{NewSet().(fmt.Stringer), "Set{}"},
+		{NewOrderedSet("b", "a").(fmt.Stringer), "OrderedSet{b, a}"},
+		{NewUnsafeOrderedSetFromSlice([]interface{}{3, 1, 2}).(fmt.Stringer), "OrderedSet{3, 1, 2}"},
+		{NewTypedSet[uint8](9, 10, 1).(fmt.Stringer), "Set{1, 9, 10}"},
+	} {
+		if got := tt.s.String(); got != tt.want {
+			t.Errorf("expected %v, got %v", tt.want, got)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, tt := range []struct {
+		format string
+		s      interface{}
+		want   string
+	}{
+		{"%v", NewSet(2, 1), "Set{1, 2}"},
+		{"%x", NewOrderedSet(255, 16), "OrderedSet{ff, 10}"},
+		{"%q", NewTypedUnsafeOrderedSetFromSlice([]string{"b", "a"}), `OrderedSet{"b", "a"}`},
+		{"%#v", NewSet("b", 1), `set.NewSetFromSlice([]interface {}{1, "b"})`},
+		{"%#v", NewUnsafeSetFromSlice([]interface{}{2, 1}), `set.NewUnsafeSetFromSlice([]interface {}{1, 2})`},
+		{"%#v", NewOrderedSet("b", "a"), `set.NewOrderedSetFromSlice([]interface {}{"b", "a"})`},
+		{"%#v", NewTypedUnsafeOrderedSet[int](), `set.NewTypedUnsafeOrderedSetFromSlice([]int{})`},
+		{"%#v", NewTypedSet("a"), `set.NewTypedSetFromSlice([]string{"a"})`},
+	} {
+		if got := fmt.Sprintf(tt.format, tt.s); got != tt.want {
+			t.Errorf("%v: expected %v, got %v", tt.format, tt.want, got)
+		}
+	}
+}
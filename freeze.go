@@ -0,0 +1,129 @@
+package set
+
+import "errors"
+
+var (
+	_ Freezer = (*unsafeSet[interface{}])(nil)
+	_ Freezer = (*safeSet[interface{}])(nil)
+	_ Freezer = (*unsafeOrderedSet[interface{}])(nil)
+	_ Freezer = (*safeOrderedSet[interface{}])(nil)
+	_ Freezer = (*unsafeSortedSet[interface{}])(nil)
+	_ Freezer = (*safeSortedSet[interface{}])(nil)
+	_ Freezer = (*unsafeHashSet[interface{}])(nil)
+	_ Freezer = (*safeHashSet[interface{}])(nil)
+	_ Freezer = (*shardedSet[interface{}])(nil)
+	_ Freezer = (*cowSet[interface{}])(nil)
+)
+
+// ErrFrozen is the value mutators panic with once a set has been frozen.
+// TryAdd and the JSON and binary decoders return it instead.
+var ErrFrozen = errors.New("set: set is frozen")
+
+// checkFrozen panics with ErrFrozen if frozen is set.
+func checkFrozen(frozen bool) {
+	if frozen {
+		panic(ErrFrozen)
+	}
+}
+
+func (s *unsafeSet[T]) Freeze() {
+	s.frozen = true
+}
+
+func (s *unsafeSet[T]) Frozen() bool {
+	return s.frozen
+}
+
+func (s *safeSet[T]) Freeze() {
+	s.Lock()
+	defer s.Unlock()
+	s.s.frozen = true
+}
+
+func (s *safeSet[T]) Frozen() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.frozen
+}
+
+func (s *unsafeOrderedSet[T]) Freeze() {
+	s.frozen = true
+}
+
+func (s *unsafeOrderedSet[T]) Frozen() bool {
+	return s.frozen
+}
+
+func (s *safeOrderedSet[T]) Freeze() {
+	s.Lock()
+	defer s.Unlock()
+	s.s.frozen = true
+}
+
+func (s *safeOrderedSet[T]) Frozen() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.frozen
+}
+
+func (s *unsafeSortedSet[T]) Freeze() {
+	s.frozen = true
+}
+
+func (s *unsafeSortedSet[T]) Frozen() bool {
+	return s.frozen
+}
+
+func (s *safeSortedSet[T]) Freeze() {
+	s.Lock()
+	defer s.Unlock()
+	s.s.frozen = true
+}
+
+func (s *safeSortedSet[T]) Frozen() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.frozen
+}
+
+func (s *unsafeHashSet[T]) Freeze() {
+	s.frozen = true
+}
+
+func (s *unsafeHashSet[T]) Frozen() bool {
+	return s.frozen
+}
+
+func (s *safeHashSet[T]) Freeze() {
+	s.Lock()
+	defer s.Unlock()
+	s.s.frozen = true
+}
+
+func (s *safeHashSet[T]) Frozen() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.frozen
+}
+
+// Freeze waits for every shard, so no change is in progress once it
+// returns.
+func (s *shardedSet[T]) Freeze() {
+	unlock := s.lockAll()
+	defer unlock()
+	s.frozen.Store(true)
+}
+
+func (s *shardedSet[T]) Frozen() bool {
+	return s.frozen.Load()
+}
+
+func (s *cowSet[T]) Freeze() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frozen.Store(true)
+}
+
+func (s *cowSet[T]) Frozen() bool {
+	return s.frozen.Load()
+}
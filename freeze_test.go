@@ -0,0 +1,166 @@
+package set
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func freeze(s Set) {
+	s.(Freezer).Freeze()
+}
+
+func frozen(s Set) bool {
+	return s.(Freezer).Frozen()
+}
+
+// panicsFrozen reports whether fn panics with ErrFrozen.
+func panicsFrozen(fn func()) (frozen bool) {
+	defer func() {
+		frozen = recover() == ErrFrozen
+	}()
+	fn()
+	return false
+}
+
+func TestFreeze(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+		if frozen(s) {
+			t.Fatalf("%v: a new set should not be frozen", name)
+		}
+		freeze(s)
+		if !frozen(s) {
+			t.Fatalf("%v: expected the set to be frozen", name)
+		}
+
+		for op, fn := range map[string]func(){
+			"Add":                func() { s.Add(4) },
+			"Add existing":       func() { s.Add(1) },
+			"Remove":             func() { s.Remove(1) },
+			"Remove missing":     func() { s.Remove(9) },
+			"Clear":              func() { s.Clear() },
+			"AddFrom":            func() { s.AddFrom(a(4)) },
+			"AddFrom other":      func() { s.AddFrom(NewUnsafeSet()) },
+			"RemoveFrom":         func() { s.RemoveFrom(a(1)) },
+			"RemoveFrom other":   func() { s.RemoveFrom(NewUnsafeSet()) },
+			"RetainFrom":         func() { s.RetainFrom(a(1)) },
+			"RetainFrom other":   func() { s.RetainFrom(NewUnsafeSet()) },
+			"RemoveFrom itself":  func() { s.RemoveFrom(s) },
+			"RetainFrom itself":  func() { s.RetainFrom(s) },
+			"AddFrom itself":     func() { s.AddFrom(s) },
+			"Freeze then Remove": func() { freeze(s); s.Remove(2) },
+		} {
+			if !panicsFrozen(fn) {
+				t.Errorf("%v: expected %v to panic with ErrFrozen", name, op)
+			}
+		}
+
+		if err := s.TryAdd(4); err != ErrFrozen {
+			t.Errorf("%v: expected ErrFrozen from TryAdd, got %v", name, err)
+		}
+		if !s.Equal(a(1, 2, 3)) || s.Contains(4) {
+			t.Errorf("%v: a frozen set should not change, got %v", name, s)
+		}
+	}
+}
+
+func TestFreezeKeepsReads(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+		freeze(s)
+
+		if s.Len() != 3 || !s.Contains(1, 2, 3) || len(s.ToSlice()) != 3 {
+			t.Errorf("%v: expected to read {1, 2, 3}, got %v", name, s)
+		}
+		if !s.Union(a(4)).Equal(a(1, 2, 3, 4)) || !s.Difference(a(1)).Equal(a(2, 3)) {
+			t.Errorf("%v: set algebra should work on a frozen set", name)
+		}
+		if frozen(s.Union(a(4))) || frozen(s.Intersect(a(1))) {
+			t.Errorf("%v: results of set algebra should not be frozen", name)
+		}
+		if !s.IsSuperset(a(1)) || s.IsDisjoint(a(1)) {
+			t.Errorf("%v: wrong predicates on a frozen set", name)
+		}
+
+		c := s.Clone()
+		if frozen(c) {
+			t.Fatalf("%v: a clone should not be frozen", name)
+		}
+		c.Add(4)
+		if !c.Contains(4) || s.Contains(4) {
+			t.Errorf("%v: expected only the clone to change", name)
+		}
+	}
+}
+
+func TestFreezeOrdered(t *testing.T) {
+	for _, s := range []OrderedSet{NewOrderedSet(1, 2, 3).(OrderedSet), NewUnsafeOrderedSetFromSlice([]interface{}{1, 2, 3}).(OrderedSet)} {
+		freeze(s)
+		for op, fn := range map[string]func(){
+			"PopFirst":     func() { s.PopFirst() },
+			"PopLast":      func() { s.PopLast() },
+			"MoveToFront":  func() { s.MoveToFront(3) },
+			"MoveToBack":   func() { s.MoveToBack(1) },
+			"InsertAt":     func() { s.InsertAt(0, 4) },
+			"InsertBefore": func() { s.InsertBefore(1, 4) },
+			"InsertAfter":  func() { s.InsertAfter(1, 4) },
+		} {
+			if !panicsFrozen(fn) {
+				t.Errorf("%T: expected %v to panic with ErrFrozen", s, op)
+			}
+		}
+
+		if first, _ := s.First(); first != 1 || s.Len() != 3 {
+			t.Errorf("%T: a frozen set should not change, got %v", s, s)
+		}
+	}
+}
+
+func TestFreezeDecode(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1)
+		freeze(s)
+
+		if err := json.Unmarshal([]byte(`[2]`), s); !errors.Is(err, ErrFrozen) {
+			t.Errorf("%v: expected ErrFrozen from UnmarshalJSON, got %v", name, err)
+		}
+
+		b, err := a(2).(interface{ MarshalBinary() ([]byte, error) }).MarshalBinary()
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		if err := s.(interface{ UnmarshalBinary([]byte) error }).UnmarshalBinary(b); err != ErrFrozen {
+			t.Errorf("%v: expected ErrFrozen from UnmarshalBinary, got %v", name, err)
+		}
+
+		if !s.Equal(a(1)) {
+			t.Errorf("%v: decoding should not change a frozen set, got %v", name, s)
+		}
+	}
+}
+
+func TestFreezeConcurrent(t *testing.T) {
+	for _, s := range []Set{NewSet(), NewOrderedSet(), NewShardedSet(4), NewCopyOnWriteSet()} {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					if err := s.TryAdd(i*100 + j); err != nil && err != ErrFrozen {
+						t.Errorf("%T: unexpected error %v", s, err)
+					}
+				}
+			}(i)
+		}
+
+		freeze(s)
+		n := s.Len()
+		wg.Wait()
+		if s.Len() != n {
+			t.Errorf("%T: the set changed after Freeze returned, from %v to %v elements", s, n, s.Len())
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/jtejido/set
+
+go 1.21
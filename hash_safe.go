@@ -0,0 +1,211 @@
+package set
+
+import "sync"
+
+var (
+	shs *safeHashSet[interface{}]
+	_   Set = shs
+)
+
+type safeHashSet[T any] struct {
+	s *unsafeHashSet[T]
+	sync.RWMutex
+	lockOrder
+}
+
+func newSafeHashSet[T any](hash func(elem T) uint64, equal func(a, b T) bool) safeHashSet[T] {
+	return safeHashSet[T]{s: newUnsafeHashSet(hash, equal)}
+}
+
+func (s *safeHashSet[T]) Add(i ...T) {
+	s.Lock()
+	defer s.Unlock()
+	s.s.Add(i...)
+}
+
+func (s *safeHashSet[T]) Contains(i ...T) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Contains(i...)
+}
+
+func (s *safeHashSet[T]) Clear() {
+	s.Lock()
+	defer s.Unlock()
+	s.s.Clear()
+}
+
+func (s *safeHashSet[T]) Remove(i T) {
+	s.Lock()
+	defer s.Unlock()
+	s.s.Remove(i)
+}
+
+func (s *safeHashSet[T]) Len() int {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Len()
+}
+
+func (s *safeHashSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *safeHashSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
+}
+
+func (s *safeHashSet[T]) Each(fn func(elem T) bool) {
+	s.RLock()
+	defer s.RUnlock()
+	s.s.Each(fn)
+}
+
+func (s *safeHashSet[T]) Equal(other Collection[T]) bool {
+	o, ok := other.(*safeHashSet[T])
+	if !ok {
+		elems := toSlice(other)
+		s.RLock()
+		defer s.RUnlock()
+		return len(elems) == s.s.Len() && s.s.Contains(elems...)
+	}
+
+	unlock := lockPair(s, o, false)
+	defer unlock()
+	return s.s.Equal(o.s)
+}
+
+func (s *safeHashSet[T]) Clone() TypedSet[T] {
+	s.RLock()
+	defer s.RUnlock()
+	return &safeHashSet[T]{s: s.s.Clone().(*unsafeHashSet[T])}
+}
+
+func (s *safeHashSet[T]) ToSlice() []T {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.ToSlice()
+}
+
+func (s *safeHashSet[T]) RemoveFrom(other Collection[T]) {
+	o, ok := other.(*safeHashSet[T])
+	if !ok {
+		elems := toSlice(other)
+		s.Lock()
+		defer s.Unlock()
+		checkFrozen(s.s.frozen)
+		for _, elem := range elems {
+			s.s.Remove(elem)
+		}
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.RemoveFrom(o.s)
+}
+
+func (s *safeHashSet[T]) AddFrom(other Collection[T]) {
+	o, ok := other.(*safeHashSet[T])
+	if !ok {
+		elems := toSlice(other)
+		s.Lock()
+		defer s.Unlock()
+		s.s.Add(elems...)
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.AddFrom(o.s)
+}
+
+func (s *safeHashSet[T]) RetainFrom(other Collection[T]) {
+	o, ok := other.(*safeHashSet[T])
+	if !ok {
+		keep := s.snapshot(other)
+		s.Lock()
+		defer s.Unlock()
+		s.s.RetainFrom(keep)
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.RetainFrom(o.s)
+}
+
+func (s *safeHashSet[T]) Union(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeHashSet[T]).Union)
+}
+
+func (s *safeHashSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeHashSet[T]).Intersect)
+}
+
+func (s *safeHashSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeHashSet[T]).Difference)
+}
+
+func (s *safeHashSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeHashSet[T]).SymmetricDifference)
+}
+
+// combine applies op to the unsafe contents of s and other, holding both
+// read locks when other is a safeHashSet and working on a snapshot of
+// other otherwise.
+func (s *safeHashSet[T]) combine(other Collection[T], op func(*unsafeHashSet[T], Collection[T]) TypedSet[T]) TypedSet[T] {
+	if o, ok := other.(*safeHashSet[T]); ok {
+		unlock := lockPair(s, o, false)
+		defer unlock()
+		return &safeHashSet[T]{s: op(s.s, o.s).(*unsafeHashSet[T])}
+	}
+
+	snapshot := s.snapshot(other)
+	s.RLock()
+	defer s.RUnlock()
+	return &safeHashSet[T]{s: op(s.s, snapshot).(*unsafeHashSet[T])}
+}
+
+func (s *safeHashSet[T]) IsSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeHashSet[T]).IsSubset, isSubset[T])
+}
+
+func (s *safeHashSet[T]) IsSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeHashSet[T]).IsSuperset, isSuperset[T])
+}
+
+func (s *safeHashSet[T]) IsProperSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeHashSet[T]).IsProperSubset, isProperSubset[T])
+}
+
+func (s *safeHashSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeHashSet[T]).IsProperSuperset, isProperSuperset[T])
+}
+
+func (s *safeHashSet[T]) IsDisjoint(other Collection[T]) bool {
+	return s.relate(other, (*unsafeHashSet[T]).IsDisjoint, isDisjoint[T])
+}
+
+// relate applies op to the unsafe contents of s and other, holding both
+// read locks, when other is a safeHashSet, and otherwise answers with
+// fallback, which reads the two sets one at a time.
+func (s *safeHashSet[T]) relate(other Collection[T], op func(*unsafeHashSet[T], Collection[T]) bool, fallback func(s, other Collection[T]) bool) bool {
+	if o, ok := other.(*safeHashSet[T]); ok {
+		unlock := lockPair(s, o, false)
+		defer unlock()
+		return op(s.s, o.s)
+	}
+
+	return fallback(s, other)
+}
+
+// snapshot copies other into an unsafeHashSet using the hash and equality
+// of s, so that other is read before the lock on s is taken.
+func (s *safeHashSet[T]) snapshot(other Collection[T]) *unsafeHashSet[T] {
+	s.RLock()
+	ret := newUnsafeHashSet(s.s.hash, s.s.equal)
+	s.RUnlock()
+	ret.Add(toSlice(other)...)
+	return ret
+}
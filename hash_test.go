@@ -0,0 +1,164 @@
+package set
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// hashAny hashes the printed form of a value, which is the same for values
+// that reflect.DeepEqual considers equal.
+func hashAny(elem interface{}) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%T:%v", elem, elem)
+	return h.Sum64()
+}
+
+func hashInts(elem []int) uint64 {
+	return hashAny(elem)
+}
+
+func equalInts(a, b []int) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func init() {
+	constructors["HashSet"] = func(s ...interface{}) Set {
+		return NewHashSet(hashAny, reflect.DeepEqual, s...)
+	}
+	constructors["UnsafeHashSet"] = func(s ...interface{}) Set {
+		return NewUnsafeHashSetFromSlice(hashAny, reflect.DeepEqual, s)
+	}
+}
+
+func TestHashSetSlices(t *testing.T) {
+	for _, s := range []TypedSet[[]int]{
+		NewTypedHashSet(hashInts, equalInts),
+		NewTypedUnsafeHashSet(hashInts, equalInts),
+	} {
+		s.Add([]int{1, 2}, []int{3}, []int{1, 2})
+		if s.Len() != 2 {
+			t.Fatalf("expected 2 elements, got %v", s.Len())
+		}
+
+		if !s.Contains([]int{1, 2}, []int{3}) || s.Contains([]int{2, 1}) {
+			t.Errorf("wrong membership: %v", s)
+		}
+
+		s.Remove([]int{1, 2})
+		if s.Len() != 1 || s.Contains([]int{1, 2}) {
+			t.Errorf("expected {[3]}, got %v", s)
+		}
+	}
+}
+
+func TestHashSetCollisions(t *testing.T) {
+	collide := func(string) uint64 { return 7 }
+	s := NewTypedUnsafeHashSet(collide, strings.EqualFold)
+	s.Add("a", "B", "c", "A", "b")
+	if s.Len() != 3 {
+		t.Fatalf("expected 3 elements, got %v", s.Len())
+	}
+
+	if !s.Contains("A", "b", "C") {
+		t.Errorf("expected case-insensitive membership, got %v", s)
+	}
+
+	s.Remove("a")
+	s.Remove("x")
+	if s.Len() != 2 || s.Contains("a") || !s.Contains("b", "c") {
+		t.Errorf("expected {B, c}, got %v", s)
+	}
+
+	s.Remove("b")
+	s.Remove("C")
+	if s.Len() != 0 || len(s.(*unsafeHashSet[string]).buckets) != 0 {
+		t.Errorf("expected an empty set, got %v", s)
+	}
+}
+
+func TestHashSetRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := NewTypedHashSet(func(i int) uint64 { return uint64(i % 16) }, func(a, b int) bool { return a == b })
+	model := map[int]bool{}
+
+	for i := 0; i < 5000; i++ {
+		v := r.Intn(200)
+		if r.Intn(3) == 0 {
+			s.Remove(v)
+			delete(model, v)
+		} else {
+			s.Add(v)
+			model[v] = true
+		}
+
+		if s.Len() != len(model) {
+			t.Fatalf("expected %v elements, got %v", len(model), s.Len())
+		}
+	}
+
+	for v := 0; v < 200; v++ {
+		if s.Contains(v) != model[v] {
+			t.Fatalf("expected Contains(%v) to be %v", v, model[v])
+		}
+	}
+}
+
+func TestHashSetClone(t *testing.T) {
+	s := NewTypedHashSet(hashInts, equalInts, []int{1}, []int{2})
+	c := s.Clone()
+	c.Add([]int{3})
+	s.Remove([]int{1})
+
+	if s.Len() != 1 || c.Len() != 3 || !c.Contains([]int{1}) {
+		t.Errorf("clone is not independent: %v and %v", s, c)
+	}
+}
+
+func TestHashSetAlgebra(t *testing.T) {
+	a := NewTypedHashSet(hashInts, equalInts, []int{1}, []int{2}, []int{3})
+	b := NewTypedUnsafeHashSet(hashInts, equalInts)
+	b.Add([]int{2}, []int{3}, []int{4})
+
+	if u := a.Union(b); u.Len() != 4 {
+		t.Errorf("expected 4 elements in the union, got %v", u)
+	}
+	if i := a.Intersect(b); !i.Equal(NewTypedUnsafeHashSetFromSlice(hashInts, equalInts, [][]int{{2}, {3}})) {
+		t.Errorf("expected {[2], [3]}, got %v", i)
+	}
+	if d := a.SymmetricDifference(b); !d.Contains([]int{1}, []int{4}) || d.Len() != 2 {
+		t.Errorf("expected {[1], [4]}, got %v", d)
+	}
+
+	a.RetainFrom(b)
+	if !a.IsSubset(b) || !b.IsProperSuperset(a) {
+		t.Errorf("expected %v to be a proper subset of %v", a, b)
+	}
+}
+
+func TestHashSetStringAndJSON(t *testing.T) {
+	s := NewTypedHashSet(hashInts, equalInts, []int{2}, []int{1, 1})
+	if got := fmt.Sprint(s); got != "Set{[1 1], [2]}" {
+		t.Errorf("unexpected string %q", got)
+	}
+	if got := fmt.Sprintf("%#v", NewHashSet(hashAny, reflect.DeepEqual, 1)); got != "set.NewHashSetFromSlice(hash, equal, []interface {}{1})" {
+		t.Errorf("unexpected Go syntax %q", got)
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := NewTypedUnsafeHashSet(hashInts, equalInts)
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(s) {
+		t.Errorf("expected %v, got %v", s, decoded)
+	}
+}
@@ -0,0 +1,229 @@
+package set
+
+var (
+	uhs *unsafeHashSet[interface{}]
+	_   Set = uhs
+)
+
+// unsafeHashSet buckets its elements by a user-supplied hash and tells them
+// apart with a user-supplied equality, so it can hold elements that Go maps
+// cannot, such as slices, or compare them loosely, such as strings without
+// regard to case. Elements that are equal must hash the same.
+type unsafeHashSet[T any] struct {
+	buckets map[uint64][]T
+	len     int
+	hash    func(elem T) uint64
+	equal   func(a, b T) bool
+	frozen  bool
+}
+
+func newUnsafeHashSet[T any](hash func(elem T) uint64, equal func(a, b T) bool) *unsafeHashSet[T] {
+	return &unsafeHashSet[T]{buckets: make(map[uint64][]T), hash: hash, equal: equal}
+}
+
+// find returns the hash of elem and its position in that bucket, or -1.
+func (s *unsafeHashSet[T]) find(elem T) (uint64, int) {
+	h := s.hash(elem)
+	for i, item := range s.buckets[h] {
+		if s.equal(item, elem) {
+			return h, i
+		}
+	}
+	return h, -1
+}
+
+func (s *unsafeHashSet[T]) Add(i ...T) {
+	checkFrozen(s.frozen)
+	for _, item := range i {
+		h, j := s.find(item)
+		if j >= 0 {
+			continue
+		}
+
+		s.buckets[h] = append(s.buckets[h], item)
+		s.len++
+	}
+}
+
+func (s *unsafeHashSet[T]) Contains(i ...T) bool {
+	for _, item := range i {
+		if _, j := s.find(item); j < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeHashSet[T]) Clear() {
+	checkFrozen(s.frozen)
+	s.buckets, s.len = make(map[uint64][]T), 0
+}
+
+func (s *unsafeHashSet[T]) Remove(i T) {
+	checkFrozen(s.frozen)
+	h, j := s.find(i)
+	if j < 0 {
+		return
+	}
+
+	bucket := s.buckets[h]
+	if len(bucket) == 1 {
+		delete(s.buckets, h)
+	} else {
+		bucket[j] = bucket[len(bucket)-1]
+		var zero T
+		bucket[len(bucket)-1] = zero
+		s.buckets[h] = bucket[:len(bucket)-1]
+	}
+	s.len--
+}
+
+func (s *unsafeHashSet[T]) Len() int {
+	return s.len
+}
+
+func (s *unsafeHashSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *unsafeHashSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
+}
+
+func (s *unsafeHashSet[T]) Each(fn func(elem T) bool) {
+	for _, bucket := range s.buckets {
+		for _, item := range bucket {
+			if !fn(item) {
+				return
+			}
+		}
+	}
+}
+
+func (s *unsafeHashSet[T]) Equal(other Collection[T]) bool {
+	if s.Len() != other.Len() {
+		return false
+	}
+	return s.IsSubset(other)
+}
+
+func (s *unsafeHashSet[T]) Clone() TypedSet[T] {
+	clonedSet := newUnsafeHashSet(s.hash, s.equal)
+	for h, bucket := range s.buckets {
+		clonedSet.buckets[h] = append([]T(nil), bucket...)
+	}
+	clonedSet.len = s.len
+	return clonedSet
+}
+
+func (s *unsafeHashSet[T]) ToSlice() []T {
+	keys := make([]T, 0, s.Len())
+	for _, bucket := range s.buckets {
+		keys = append(keys, bucket...)
+	}
+	return keys
+}
+
+func (s *unsafeHashSet[T]) RemoveFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	for _, elem := range s.ToSlice() {
+		if contains(other, elem) {
+			s.Remove(elem)
+		}
+	}
+}
+
+func (s *unsafeHashSet[T]) AddFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	s.Add(toSlice(other)...)
+}
+
+func (s *unsafeHashSet[T]) RetainFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	for _, elem := range s.ToSlice() {
+		if !contains(other, elem) {
+			s.Remove(elem)
+		}
+	}
+}
+
+func (s *unsafeHashSet[T]) Union(other Collection[T]) TypedSet[T] {
+	ret := s.Clone()
+	ret.AddFrom(other)
+	return ret
+}
+
+func (s *unsafeHashSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	ret := newUnsafeHashSet(s.hash, s.equal)
+	s.Each(func(elem T) bool {
+		if contains(other, elem) {
+			ret.Add(elem)
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *unsafeHashSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	ret := newUnsafeHashSet(s.hash, s.equal)
+	s.Each(func(elem T) bool {
+		if !contains(other, elem) {
+			ret.Add(elem)
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *unsafeHashSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	ret := s.Difference(other).(*unsafeHashSet[T])
+	for _, elem := range toSlice(other) {
+		if !s.Contains(elem) {
+			ret.Add(elem)
+		}
+	}
+	return ret
+}
+
+func (s *unsafeHashSet[T]) IsSubset(other Collection[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+
+	ret := true
+	s.Each(func(elem T) bool {
+		ret = contains(other, elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *unsafeHashSet[T]) IsSuperset(other Collection[T]) bool {
+	if s.Len() < other.Len() {
+		return false
+	}
+
+	ret := true
+	other.Each(func(elem T) bool {
+		ret = s.Contains(elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *unsafeHashSet[T]) IsProperSubset(other Collection[T]) bool {
+	return s.Len() < other.Len() && s.IsSubset(other)
+}
+
+func (s *unsafeHashSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return s.Len() > other.Len() && s.IsSuperset(other)
+}
+
+func (s *unsafeHashSet[T]) IsDisjoint(other Collection[T]) bool {
+	ret := true
+	s.Each(func(elem T) bool {
+		ret = !contains(other, elem)
+		return ret
+	})
+	return ret
+}
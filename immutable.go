@@ -0,0 +1,388 @@
+package set
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+var (
+	ims *immutableSet[interface{}]
+	_   ImmutableSet = ims
+)
+
+// TypedImmutableSet is a persistent set of elements of type T. It has no
+// methods that change it: With, Without and the set algebra return new
+// versions that share most of their structure with the set they were made
+// from, and every version stays valid and unchanged. Immutable sets can be
+// handed to other goroutines without copying or locking.
+type TypedImmutableSet[T comparable] interface {
+	Lener
+	Container[T]
+	Iterable[T]
+
+	TryContains(i ...T) (bool, error)
+	Iter() <-chan T
+	Iterator() *Iterator[T]
+	ToSlice() []T
+	Equal(other TypedImmutableSet[T]) bool
+
+	// With returns a version of the set that also holds elems, and
+	// Without one that doesn't hold elems. They return the set itself when
+	// nothing changes.
+	With(elems ...T) TypedImmutableSet[T]
+	Without(elems ...T) TypedImmutableSet[T]
+
+	Union(other TypedImmutableSet[T]) TypedImmutableSet[T]
+	Intersect(other TypedImmutableSet[T]) TypedImmutableSet[T]
+	Difference(other TypedImmutableSet[T]) TypedImmutableSet[T]
+	SymmetricDifference(other TypedImmutableSet[T]) TypedImmutableSet[T]
+
+	// ToSet returns a new thread safe mutable set holding the elements.
+	ToSet() TypedSet[T]
+}
+
+// ImmutableSet is the untyped immutable set. It is the same type as
+// TypedImmutableSet[interface{}].
+type ImmutableSet = TypedImmutableSet[interface{}]
+
+const (
+	hamtBits  = 5
+	hamtWidth = 1 << hamtBits
+)
+
+var hamtSeed = maphash.MakeSeed()
+
+func hamtHash[T comparable](elem T) uint64 {
+	return hashComparable(hamtSeed, elem)
+}
+
+// hamtNode is a node of a hash array mapped trie. Each level of the trie
+// consumes hamtBits of an element's hash to pick one of hamtWidth slots;
+// bitmap marks the slots in use and entries holds them in slot order. Once
+// all 64 bits are consumed, elements with the same hash are kept together in
+// leaves. Nodes are never changed after they are built, so versions of a set
+// can share them.
+type hamtNode[T comparable] struct {
+	bitmap  uint32
+	entries []hamtEntry[T]
+	leaves  []T
+}
+
+// hamtEntry is either a child node or a single element with its hash.
+type hamtEntry[T comparable] struct {
+	node  *hamtNode[T]
+	hash  uint64
+	value T
+}
+
+func hamtSlot(hash uint64, shift uint) uint32 {
+	return 1 << ((hash >> shift) & (hamtWidth - 1))
+}
+
+func (n *hamtNode[T]) position(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode[T]) contains(hash uint64, shift uint, v T) bool {
+	for n != nil {
+		if shift >= 64 {
+			for _, leaf := range n.leaves {
+				if leaf == v {
+					return true
+				}
+			}
+			return false
+		}
+
+		bit := hamtSlot(hash, shift)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+
+		e := n.entries[n.position(bit)]
+		if e.node == nil {
+			return e.value == v
+		}
+		n, shift = e.node, shift+hamtBits
+	}
+	return false
+}
+
+// with returns a copy of n that also holds v, or n itself if it already
+// does.
+func (n *hamtNode[T]) with(hash uint64, shift uint, v T) (*hamtNode[T], bool) {
+	if n == nil {
+		return &hamtNode[T]{bitmap: hamtSlot(hash, shift), entries: []hamtEntry[T]{{hash: hash, value: v}}}, true
+	}
+
+	if shift >= 64 {
+		for _, leaf := range n.leaves {
+			if leaf == v {
+				return n, false
+			}
+		}
+		leaves := append(append(make([]T, 0, len(n.leaves)+1), n.leaves...), v)
+		return &hamtNode[T]{leaves: leaves}, true
+	}
+
+	bit := hamtSlot(hash, shift)
+	pos := n.position(bit)
+	if n.bitmap&bit == 0 {
+		entries := make([]hamtEntry[T], len(n.entries)+1)
+		copy(entries, n.entries[:pos])
+		entries[pos] = hamtEntry[T]{hash: hash, value: v}
+		copy(entries[pos+1:], n.entries[pos:])
+		return &hamtNode[T]{bitmap: n.bitmap | bit, entries: entries}, true
+	}
+
+	e := n.entries[pos]
+	var child *hamtNode[T]
+	switch {
+	case e.node != nil:
+		var added bool
+		if child, added = e.node.with(hash, shift+hamtBits, v); !added {
+			return n, false
+		}
+	case e.value == v:
+		return n, false
+	default:
+		child = hamtPair(e, hamtEntry[T]{hash: hash, value: v}, shift+hamtBits)
+	}
+
+	return n.replace(pos, hamtEntry[T]{node: child}), true
+}
+
+// hamtPair builds the subtree holding the elements of a and b.
+func hamtPair[T comparable](a, b hamtEntry[T], shift uint) *hamtNode[T] {
+	if shift >= 64 {
+		return &hamtNode[T]{leaves: []T{a.value, b.value}}
+	}
+
+	bitA, bitB := hamtSlot(a.hash, shift), hamtSlot(b.hash, shift)
+	switch {
+	case bitA == bitB:
+		return &hamtNode[T]{bitmap: bitA, entries: []hamtEntry[T]{{node: hamtPair(a, b, shift+hamtBits)}}}
+	case bitA < bitB:
+		return &hamtNode[T]{bitmap: bitA | bitB, entries: []hamtEntry[T]{a, b}}
+	default:
+		return &hamtNode[T]{bitmap: bitA | bitB, entries: []hamtEntry[T]{b, a}}
+	}
+}
+
+// without returns a copy of n that doesn't hold v, or n itself if it
+// doesn't. An empty result is nil.
+func (n *hamtNode[T]) without(hash uint64, shift uint, v T) (*hamtNode[T], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	if shift >= 64 {
+		for i, leaf := range n.leaves {
+			if leaf == v {
+				leaves := append(append(make([]T, 0, len(n.leaves)-1), n.leaves[:i]...), n.leaves[i+1:]...)
+				if len(leaves) == 0 {
+					return nil, true
+				}
+				return &hamtNode[T]{leaves: leaves}, true
+			}
+		}
+		return n, false
+	}
+
+	bit := hamtSlot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	pos := n.position(bit)
+	e := n.entries[pos]
+	if e.node == nil {
+		if e.value != v {
+			return n, false
+		}
+		return n.drop(pos, bit), true
+	}
+
+	child, removed := e.node.without(hash, shift+hamtBits, v)
+	switch {
+	case !removed:
+		return n, false
+	case child == nil:
+		return n.drop(pos, bit), true
+	case len(child.leaves) == 1:
+		return n.replace(pos, hamtEntry[T]{hash: hash, value: child.leaves[0]}), true
+	case len(child.entries) == 1 && child.entries[0].node == nil:
+		return n.replace(pos, child.entries[0]), true
+	}
+	return n.replace(pos, hamtEntry[T]{node: child}), true
+}
+
+// replace returns a copy of n with the entry at pos set to e.
+func (n *hamtNode[T]) replace(pos int, e hamtEntry[T]) *hamtNode[T] {
+	entries := append([]hamtEntry[T](nil), n.entries...)
+	entries[pos] = e
+	return &hamtNode[T]{bitmap: n.bitmap, entries: entries}
+}
+
+// drop returns a copy of n without the entry at pos, or nil if that was the
+// only one.
+func (n *hamtNode[T]) drop(pos int, bit uint32) *hamtNode[T] {
+	if len(n.entries) == 1 {
+		return nil
+	}
+
+	entries := make([]hamtEntry[T], 0, len(n.entries)-1)
+	entries = append(append(entries, n.entries[:pos]...), n.entries[pos+1:]...)
+	return &hamtNode[T]{bitmap: n.bitmap &^ bit, entries: entries}
+}
+
+// each calls fn for every element under n until fn returns false, and
+// reports whether it got to the end.
+func (n *hamtNode[T]) each(fn func(elem T) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	for _, leaf := range n.leaves {
+		if !fn(leaf) {
+			return false
+		}
+	}
+	for _, e := range n.entries {
+		if e.node != nil {
+			if !e.node.each(fn) {
+				return false
+			}
+		} else if !fn(e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+type immutableSet[T comparable] struct {
+	root *hamtNode[T]
+	len  int
+	hash func(elem T) uint64
+}
+
+func newImmutableSet[T comparable]() *immutableSet[T] {
+	return &immutableSet[T]{hash: hamtHash[T]}
+}
+
+func (s *immutableSet[T]) Len() int {
+	return s.len
+}
+
+func (s *immutableSet[T]) Contains(i ...T) bool {
+	for _, item := range i {
+		if !s.root.contains(s.hash(item), 0, item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *immutableSet[T]) TryContains(i ...T) (bool, error) {
+	if err := checkHashable(i); err != nil {
+		return false, err
+	}
+	return s.Contains(i...), nil
+}
+
+func (s *immutableSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *immutableSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
+}
+
+func (s *immutableSet[T]) Each(fn func(elem T) bool) {
+	s.root.each(fn)
+}
+
+func (s *immutableSet[T]) ToSlice() []T {
+	keys := make([]T, 0, s.len)
+	s.root.each(func(elem T) bool {
+		keys = append(keys, elem)
+		return true
+	})
+	return keys
+}
+
+func (s *immutableSet[T]) Equal(other TypedImmutableSet[T]) bool {
+	if o, ok := other.(*immutableSet[T]); ok && o.root == s.root {
+		return true
+	}
+	if s.Len() != other.Len() {
+		return false
+	}
+
+	ret := true
+	other.Each(func(elem T) bool {
+		ret = s.Contains(elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *immutableSet[T]) With(elems ...T) TypedImmutableSet[T] {
+	return s.with(elems...)
+}
+
+func (s *immutableSet[T]) with(elems ...T) *immutableSet[T] {
+	ret := s
+	for _, elem := range elems {
+		if root, added := ret.root.with(s.hash(elem), 0, elem); added {
+			ret = &immutableSet[T]{root: root, len: ret.len + 1, hash: s.hash}
+		}
+	}
+	return ret
+}
+
+func (s *immutableSet[T]) Without(elems ...T) TypedImmutableSet[T] {
+	return s.without(elems...)
+}
+
+func (s *immutableSet[T]) without(elems ...T) *immutableSet[T] {
+	ret := s
+	for _, elem := range elems {
+		if root, removed := ret.root.without(s.hash(elem), 0, elem); removed {
+			ret = &immutableSet[T]{root: root, len: ret.len - 1, hash: s.hash}
+		}
+	}
+	return ret
+}
+
+// Union adds the elements of the smaller set to the larger one, so the
+// result shares the larger set's structure.
+func (s *immutableSet[T]) Union(other TypedImmutableSet[T]) TypedImmutableSet[T] {
+	if o, ok := other.(*immutableSet[T]); ok && o.len > s.len {
+		return o.with(s.ToSlice()...)
+	}
+	return s.with(other.ToSlice()...)
+}
+
+func (s *immutableSet[T]) Intersect(other TypedImmutableSet[T]) TypedImmutableSet[T] {
+	var drop []T
+	s.Each(func(elem T) bool {
+		if !other.Contains(elem) {
+			drop = append(drop, elem)
+		}
+		return true
+	})
+	return s.without(drop...)
+}
+
+func (s *immutableSet[T]) Difference(other TypedImmutableSet[T]) TypedImmutableSet[T] {
+	return s.without(other.ToSlice()...)
+}
+
+func (s *immutableSet[T]) SymmetricDifference(other TypedImmutableSet[T]) TypedImmutableSet[T] {
+	return s.Difference(other).Union(other.Difference(s))
+}
+
+func (s *immutableSet[T]) ToSet() TypedSet[T] {
+	return NewTypedSetFromSlice(s.ToSlice())
+}
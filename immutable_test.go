@@ -0,0 +1,222 @@
+package set
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// checkImmutable compares every version against the model it should match.
+func checkImmutable(t *testing.T, versions []*immutableSet[int], models []map[int]bool) {
+	t.Helper()
+	for i, s := range versions {
+		if s.Len() != len(models[i]) || len(s.ToSlice()) != len(models[i]) {
+			t.Fatalf("version %v: expected %v elements, got %v", i, len(models[i]), s.Len())
+		}
+		for v := range models[i] {
+			if !s.Contains(v) {
+				t.Fatalf("version %v: missing %v", i, v)
+			}
+		}
+	}
+}
+
+func testImmutableRandom(t *testing.T, hash func(int) uint64) {
+	r := rand.New(rand.NewSource(1))
+	s := &immutableSet[int]{hash: hash}
+	model := map[int]bool{}
+	versions := []*immutableSet[int]{s}
+	models := []map[int]bool{{}}
+
+	for i := 0; i < 2000; i++ {
+		v := r.Intn(300)
+		next := make(map[int]bool, len(model)+1)
+		for k := range model {
+			next[k] = true
+		}
+
+		if r.Intn(3) == 0 {
+			s = s.without(v)
+			delete(next, v)
+		} else {
+			s = s.with(v)
+			next[v] = true
+		}
+
+		model = next
+		if i%50 == 0 {
+			versions, models = append(versions, s), append(models, model)
+		}
+	}
+
+	versions, models = append(versions, s), append(models, model)
+	checkImmutable(t, versions, models)
+}
+
+func TestImmutableRandom(t *testing.T) {
+	testImmutableRandom(t, hamtHash[int])
+}
+
+func TestImmutableCollisions(t *testing.T) {
+	// Hashes sharing their low bits build long single-child chains, and
+	// hashes that are equal end in leaves.
+	testImmutableRandom(t, func(v int) uint64 { return uint64(v) << 40 })
+	testImmutableRandom(t, func(v int) uint64 { return uint64(v % 7) })
+}
+
+func TestImmutableFullCollisions(t *testing.T) {
+	s := &immutableSet[int]{hash: func(int) uint64 { return 42 }}
+	s = s.with(1, 2, 3, 2)
+	if s.Len() != 3 || !s.Contains(1, 2, 3) || s.Contains(4) {
+		t.Fatalf("expected {1, 2, 3}, got %v", s)
+	}
+
+	for _, v := range []int{2, 1, 3} {
+		s = s.without(v)
+		if s.Contains(v) {
+			t.Errorf("expected %v to be removed", v)
+		}
+	}
+	if s.Len() != 0 || s.root != nil {
+		t.Errorf("expected an empty set, got %v", s)
+	}
+}
+
+func TestImmutableVersionsAreIndependent(t *testing.T) {
+	a := NewTypedImmutableSet(1, 2, 3)
+	b := a.With(4)
+	c := b.Without(1)
+
+	if !a.Equal(NewTypedImmutableSet(1, 2, 3)) || !b.Equal(NewTypedImmutableSet(1, 2, 3, 4)) || !c.Equal(NewTypedImmutableSet(2, 3, 4)) {
+		t.Errorf("versions changed: %v, %v, %v", a, b, c)
+	}
+
+	if a.With(1, 2) != a || a.Without(9) != a {
+		t.Error("a version that changes nothing should be the same set")
+	}
+}
+
+func TestImmutableSharesStructure(t *testing.T) {
+	a := NewTypedImmutableSet[int]()
+	for i := 0; i < N; i++ {
+		a = a.With(i)
+	}
+	b := a.With(N)
+
+	ra, rb := a.(*immutableSet[int]).root, b.(*immutableSet[int]).root
+	shared := 0
+	for i := range ra.entries {
+		if ra.entries[i].node != nil && ra.entries[i].node == rb.entries[i].node {
+			shared++
+		}
+	}
+	if shared < len(ra.entries)-1 {
+		t.Errorf("expected all but one subtree to be shared, got %v of %v", shared, len(ra.entries))
+	}
+}
+
+func TestImmutableAlgebra(t *testing.T) {
+	a := NewImmutableSet(1, 2, 3)
+	b := NewImmutableSet(2, 3, 4, 5)
+
+	for _, c := range []struct {
+		got, want ImmutableSet
+	}{
+		{a.Union(b), NewImmutableSet(1, 2, 3, 4, 5)},
+		{b.Union(a), NewImmutableSet(1, 2, 3, 4, 5)},
+		{a.Intersect(b), NewImmutableSet(2, 3)},
+		{a.Difference(b), NewImmutableSet(1)},
+		{a.SymmetricDifference(b), NewImmutableSet(1, 4, 5)},
+	} {
+		if !c.got.Equal(c.want) {
+			t.Errorf("expected %v, got %v", c.want, c.got)
+		}
+	}
+
+	if !a.Equal(NewImmutableSet(3, 2, 1)) || a.Equal(b) {
+		t.Errorf("wrong equality for %v", a)
+	}
+}
+
+func TestImmutableConversions(t *testing.T) {
+	for name, c := range constructors {
+		s := c(1, 2, 3)
+		i := NewImmutableSetFromSet(s)
+		s.Add(4)
+
+		if !i.Equal(NewImmutableSet(1, 2, 3)) {
+			t.Errorf("%v: expected {1, 2, 3}, got %v", name, i)
+		}
+
+		m := i.ToSet()
+		m.Add(5)
+		if !m.Equal(NewSet(1, 2, 3, 5)) || i.Contains(5) {
+			t.Errorf("%v: expected an independent mutable copy, got %v", name, m)
+		}
+	}
+}
+
+func TestImmutableString(t *testing.T) {
+	s := NewImmutableSet(3, 1, 2)
+	if got := fmt.Sprint(s); got != "ImmutableSet{1, 2, 3}" {
+		t.Errorf("unexpected string %q", got)
+	}
+	if got := fmt.Sprintf("%#v", NewTypedImmutableSet("a")); got != `set.NewTypedImmutableSetFromSlice([]string{"a"})` {
+		t.Errorf("unexpected Go syntax %q", got)
+	}
+}
+
+func TestImmutableConcurrentReaders(t *testing.T) {
+	s := NewTypedImmutableSet[int]()
+	for i := 0; i < N; i++ {
+		s = s.With(i)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			mine := s
+			for i := 0; i < N; i++ {
+				if !s.Contains(i) {
+					t.Errorf("missing %v", i)
+				}
+				mine = mine.Without(i).With(i + N*(w+1))
+			}
+			if mine.Len() != N {
+				t.Errorf("expected %v elements, got %v", N, mine.Len())
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if s.Len() != N {
+		t.Errorf("shared version changed to %v elements", s.Len())
+	}
+}
+
+func BenchmarkImmutableWith(b *testing.B) {
+	s := NewTypedImmutableSet[int]()
+	for i := 0; i < N; i++ {
+		s = s.With(i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.With(i + N)
+	}
+}
+
+func BenchmarkImmutableContains(b *testing.B) {
+	s := NewTypedImmutableSet[int]()
+	for i := 0; i < N; i++ {
+		s = s.With(i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Contains(i % N)
+	}
+}
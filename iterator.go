@@ -0,0 +1,44 @@
+package set
+
+// Iterator walks over a snapshot of a set's elements taken when the
+// iterator was created. It holds no locks and starts no goroutines, so it
+// can be abandoned at any point, and changes made to the set afterwards are
+// not observed.
+//
+//	it := s.Iterator()
+//	for it.Next() {
+//		fmt.Println(it.Value())
+//	}
+type Iterator[T any] struct {
+	elems []T
+	i     int
+}
+
+func newIterator[T any](elems []T) *Iterator[T] {
+	return &Iterator[T]{elems: elems, i: -1}
+}
+
+// Next advances the iterator and reports whether there is a value to read.
+func (it *Iterator[T]) Next() bool {
+	if it.i < len(it.elems) {
+		it.i++
+	}
+	return it.i < len(it.elems)
+}
+
+// Value returns the element at the current position. It panics unless the
+// last call to Next returned true.
+func (it *Iterator[T]) Value() T {
+	return it.elems[it.i]
+}
+
+// sliceChan returns a closed channel buffered with elems, so receivers can
+// stop reading at any time without leaking a sender.
+func sliceChan[T any](elems []T) <-chan T {
+	ch := make(chan T, len(elems))
+	for _, elem := range elems {
+		ch <- elem
+	}
+	close(ch)
+	return ch
+}
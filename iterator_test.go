@@ -0,0 +1,116 @@
+package set
+
+import "testing"
+
+func TestIterator(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+		b := NewSet()
+
+		it := s.Iterator()
+		for it.Next() {
+			b.Add(it.Value())
+		}
+
+		if !s.Equal(b) {
+			t.Errorf("%v: the sets are not equal after iterating (Iterator) through the first set", name)
+		}
+
+		if it.Next() {
+			t.Errorf("%v: an exhausted iterator should stay exhausted", name)
+		}
+	}
+}
+
+func TestIteratorSnapshot(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+
+		it := s.Iterator()
+		s.Clear()
+
+		n := 0
+		for it.Next() {
+			n++
+		}
+
+		if n != 3 {
+			t.Errorf("%v: expected the iterator to see 3 snapshotted elements, got %v", name, n)
+		}
+	}
+}
+
+func TestOrderedIterator(t *testing.T) {
+	for _, s := range []Set{NewOrderedSet("c", "a", "b"), NewUnsafeOrderedSetFromSlice([]interface{}{"c", "a", "b"})} {
+		var got []interface{}
+		for it := s.Iterator(); it.Next(); {
+			got = append(got, it.Value())
+		}
+
+		assertOrder(t, NewUnsafeOrderedSetFromSlice(got), "c", "a", "b")
+	}
+}
+
+func TestIterEarlyBreak(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+
+		for range s.Iter() {
+			break
+		}
+
+		s.Add(4)
+		if s.Len() != 4 {
+			t.Errorf("%v: expected 4 elements after breaking out of Iter, got %v", name, s.Len())
+		}
+	}
+}
+
+func TestEach(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+		b := NewSet()
+
+		s.Each(func(elem interface{}) bool {
+			b.Add(elem)
+			return true
+		})
+
+		if !s.Equal(b) {
+			t.Errorf("%v: the sets are not equal after walking (Each) through the first set", name)
+		}
+	}
+}
+
+func TestEachEarlyExit(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+
+		n := 0
+		s.Each(func(elem interface{}) bool {
+			n++
+			return false
+		})
+
+		if n != 1 {
+			t.Errorf("%v: expected Each to stop after 1 element, got %v", name, n)
+		}
+
+		s.Add(4)
+		if s.Len() != 4 {
+			t.Errorf("%v: expected the set to be writable after Each returned", name)
+		}
+	}
+}
+
+func TestOrderedEach(t *testing.T) {
+	for _, s := range []Set{NewOrderedSet("c", "a", "b"), NewUnsafeOrderedSetFromSlice([]interface{}{"c", "a", "b"})} {
+		var got []interface{}
+		s.Each(func(elem interface{}) bool {
+			got = append(got, elem)
+			return true
+		})
+
+		assertOrder(t, NewUnsafeOrderedSetFromSlice(got), "c", "a", "b")
+	}
+}
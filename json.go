@@ -0,0 +1,231 @@
+package set
+
+import "encoding/json"
+
+// Sets encode to JSON as arrays of their elements; ordered sets keep their
+// insertion order. Decoding replaces the contents of the set with the
+// elements of the array. Duplicates in the array are dropped, keeping the
+// position of their first occurrence. Untyped sets decode elements the way
+// encoding/json decodes into interface{}, so numbers come back as float64,
+// and map backed sets reject arrays and objects with an ErrUnhashable.
+// Sorted sets encode in sort order and decode using their own comparator,
+// and hash sets decode using their own hash and equality. Immutable sets only
+// encode; decode into a []T and pass it to NewTypedImmutableSet instead.
+
+var (
+	_ json.Marshaler   = (*unsafeSet[interface{}])(nil)
+	_ json.Unmarshaler = (*unsafeSet[interface{}])(nil)
+	_ json.Marshaler   = (*safeSet[interface{}])(nil)
+	_ json.Unmarshaler = (*safeSet[interface{}])(nil)
+	_ json.Marshaler   = (*unsafeOrderedSet[interface{}])(nil)
+	_ json.Unmarshaler = (*unsafeOrderedSet[interface{}])(nil)
+	_ json.Marshaler   = (*safeOrderedSet[interface{}])(nil)
+	_ json.Unmarshaler = (*safeOrderedSet[interface{}])(nil)
+	_ json.Marshaler   = (*unsafeSortedSet[interface{}])(nil)
+	_ json.Unmarshaler = (*unsafeSortedSet[interface{}])(nil)
+	_ json.Marshaler   = (*safeSortedSet[interface{}])(nil)
+	_ json.Unmarshaler = (*safeSortedSet[interface{}])(nil)
+	_ json.Marshaler   = (*unsafeHashSet[interface{}])(nil)
+	_ json.Unmarshaler = (*unsafeHashSet[interface{}])(nil)
+	_ json.Marshaler   = (*safeHashSet[interface{}])(nil)
+	_ json.Unmarshaler = (*safeHashSet[interface{}])(nil)
+	_ json.Marshaler   = (*shardedSet[interface{}])(nil)
+	_ json.Unmarshaler = (*shardedSet[interface{}])(nil)
+	_ json.Marshaler   = (*cowSet[interface{}])(nil)
+	_ json.Unmarshaler = (*cowSet[interface{}])(nil)
+	_ json.Marshaler   = (*immutableSet[interface{}])(nil)
+)
+
+func (s *unsafeSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *unsafeSet[T]) UnmarshalJSON(b []byte) error {
+	var elems []T
+	if err := json.Unmarshal(b, &elems); err != nil {
+		return err
+	}
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	*s = *newUnsafeSet[T]()
+	s.Add(elems...)
+	return nil
+}
+
+func (s *safeSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *safeSet[T]) UnmarshalJSON(b []byte) error {
+	decoded := newUnsafeSet[T]()
+	if err := decoded.UnmarshalJSON(b); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s != nil && s.s.frozen {
+		return ErrFrozen
+	}
+	s.s = decoded
+	return nil
+}
+
+func (s *unsafeOrderedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *unsafeOrderedSet[T]) UnmarshalJSON(b []byte) error {
+	var elems []T
+	if err := json.Unmarshal(b, &elems); err != nil {
+		return err
+	}
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	*s = *newUnsafeOrderedSet[T]()
+	s.Add(elems...)
+	return nil
+}
+
+func (s *safeOrderedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *safeOrderedSet[T]) UnmarshalJSON(b []byte) error {
+	decoded := newUnsafeOrderedSet[T]()
+	if err := decoded.UnmarshalJSON(b); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s != nil && s.s.frozen {
+		return ErrFrozen
+	}
+	s.s = decoded
+	return nil
+}
+
+func (s *unsafeSortedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *unsafeSortedSet[T]) UnmarshalJSON(b []byte) error {
+	var elems []T
+	if err := json.Unmarshal(b, &elems); err != nil {
+		return err
+	}
+
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	decoded := newUnsafeSortedSet(s.compare)
+	decoded.Add(elems...)
+	*s = *decoded
+	return nil
+}
+
+func (s *safeSortedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *safeSortedSet[T]) UnmarshalJSON(b []byte) error {
+	s.RLock()
+	decoded := newUnsafeSortedSet(s.s.compare)
+	s.RUnlock()
+	if err := decoded.UnmarshalJSON(b); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s.frozen {
+		return ErrFrozen
+	}
+	s.s = decoded
+	return nil
+}
+
+func (s *unsafeHashSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *unsafeHashSet[T]) UnmarshalJSON(b []byte) error {
+	var elems []T
+	if err := json.Unmarshal(b, &elems); err != nil {
+		return err
+	}
+
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	decoded := newUnsafeHashSet(s.hash, s.equal)
+	decoded.Add(elems...)
+	*s = *decoded
+	return nil
+}
+
+func (s *safeHashSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *safeHashSet[T]) UnmarshalJSON(b []byte) error {
+	s.RLock()
+	decoded := newUnsafeHashSet(s.s.hash, s.s.equal)
+	s.RUnlock()
+	if err := decoded.UnmarshalJSON(b); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s.frozen {
+		return ErrFrozen
+	}
+	s.s = decoded
+	return nil
+}
+
+func (s *shardedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *shardedSet[T]) UnmarshalJSON(b []byte) error {
+	var elems []T
+	if err := json.Unmarshal(b, &elems); err != nil {
+		return err
+	}
+
+	return s.replace(elems)
+}
+
+func (s *cowSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *cowSet[T]) UnmarshalJSON(b []byte) error {
+	var elems []T
+	if err := json.Unmarshal(b, &elems); err != nil {
+		return err
+	}
+
+	return s.replace(elems)
+}
+
+func (s *immutableSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
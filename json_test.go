@@ -0,0 +1,122 @@
+package set
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	for name, a := range constructors {
+		b, err := json.Marshal(a(1, 2, 3))
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		var got []int
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		if !NewTypedSetFromSlice(got).Equal(NewTypedSet(1, 2, 3)) {
+			t.Errorf("%v: expected an array of 1, 2 and 3, got %s", name, b)
+		}
+	}
+}
+
+func TestMarshalJSONOrdered(t *testing.T) {
+	for _, a := range []Set{NewOrderedSet("c", "a", "b"), NewUnsafeOrderedSetFromSlice([]interface{}{"c", "a", "b"})} {
+		b, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b) != `["c","a","b"]` {
+			t.Errorf(`expected ["c","a","b"], got %s`, b)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	for name, a := range constructors {
+		s := a("stale")
+		if err := json.Unmarshal([]byte(`["b", "a", "b", "c"]`), s); err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+
+		if !s.Equal(NewSet("a", "b", "c")) {
+			t.Errorf("%v: expected {a, b, c}, got %v", name, s.ToSlice())
+		}
+
+		if err := json.Unmarshal([]byte(`{"a": 1}`), s); err == nil {
+			t.Errorf("%v: decoding an object into a set should fail", name)
+		}
+	}
+}
+
+func TestUnmarshalJSONUnhashable(t *testing.T) {
+	for _, name := range []string{"Set", "OrderedSet", "UnsafeSet", "UnsafeOrderedSet", "ShardedSet", "CopyOnWriteSet"} {
+		for _, b := range []string{`[[1, 2]]`, `["a", {"a": 1}]`} {
+			s := constructors[name]("stale")
+			err := json.Unmarshal([]byte(b), s)
+			var unhashable ErrUnhashable
+			if !errors.As(err, &unhashable) {
+				t.Errorf("%v: expected ErrUnhashable decoding %s, got %v", name, b, err)
+			}
+			if !s.Equal(NewSet("stale")) {
+				t.Errorf("%v: a failed decode should leave the set unchanged, got %v", name, s)
+			}
+		}
+	}
+}
+
+func TestUnmarshalJSONPanicKeepsSet(t *testing.T) {
+	hash := func(elem interface{}) uint64 { return uint64(elem.(int)) }
+	equal := func(a, b interface{}) bool { return a == b }
+	compare := func(a, b interface{}) int { return cmp.Compare(a.(int), b.(int)) }
+
+	for _, s := range []Set{
+		NewHashSet(hash, equal, 1, 2),
+		NewUnsafeHashSetFromSlice(hash, equal, []interface{}{1, 2}),
+		NewSortedSet(compare, 1, 2),
+		NewUnsafeSortedSetFromSlice(compare, []interface{}{1, 2}),
+	} {
+		// Numbers decode as float64, which hash and compare reject.
+		if !mustPanic(func() { json.Unmarshal([]byte(`[3, 4]`), s) }) {
+			t.Fatalf("%T: expected decoding floats to panic", s)
+		}
+		if !s.Equal(NewSet(1, 2)) {
+			t.Errorf("%T: a panicking decode should leave the set unchanged, got %v", s, s)
+		}
+	}
+}
+
+func TestUnmarshalJSONOrdered(t *testing.T) {
+	for _, a := range []Set{NewOrderedSet(), NewUnsafeOrderedSet()} {
+		if err := json.Unmarshal([]byte(`["b", "a", "b", "c", "a"]`), a); err != nil {
+			t.Fatal(err)
+		}
+
+		assertOrder(t, a, "b", "a", "c")
+	}
+}
+
+func TestUnmarshalJSONTyped(t *testing.T) {
+	var v struct {
+		Scopes TypedSet[int]
+	}
+	v.Scopes = NewTypedSet[int]()
+
+	if err := json.Unmarshal([]byte(`{"Scopes": [3, 1, 2]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.Scopes.Equal(NewTypedSet(1, 2, 3)) {
+		t.Errorf("expected {1, 2, 3}, got %v", v.Scopes.ToSlice())
+	}
+
+	if err := json.Unmarshal([]byte(`{"Scopes": ["a"]}`), &v); err == nil {
+		t.Error("decoding strings into a TypedSet[int] should fail")
+	}
+}
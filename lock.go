@@ -0,0 +1,59 @@
+package set
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var lastLockRank atomic.Uint64
+
+// lockOrder gives each safe set a unique rank, assigned on first use, so
+// that operations on two sets always lock the lower ranked set first.
+type lockOrder struct {
+	rank atomic.Uint64
+}
+
+func (r *lockOrder) lockRank() uint64 {
+	if rank := r.rank.Load(); rank != 0 {
+		return rank
+	}
+
+	r.rank.CompareAndSwap(0, lastLockRank.Add(1))
+	return r.rank.Load()
+}
+
+type rankedLocker interface {
+	sync.Locker
+	RLock()
+	RUnlock()
+	lockRank() uint64
+}
+
+// lockPair locks s, for writing if write is set, and read locks o, taking
+// the locks in rank order so that a.AddFrom(b) and b.AddFrom(a) running at
+// the same time cannot deadlock. When s and o are the same set only the lock
+// on s is taken. It returns the function that releases the locks.
+func lockPair(s, o rankedLocker, write bool) func() {
+	lock, unlock := s.RLock, s.RUnlock
+	if write {
+		lock, unlock = s.Lock, s.Unlock
+	}
+
+	if s == o {
+		lock()
+		return unlock
+	}
+
+	if s.lockRank() < o.lockRank() {
+		lock()
+		o.RLock()
+	} else {
+		o.RLock()
+		lock()
+	}
+
+	return func() {
+		o.RUnlock()
+		unlock()
+	}
+}
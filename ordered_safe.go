@@ -3,118 +3,268 @@ package set
 import "sync"
 
 var (
-	ss *safeOrderedSet
-	_  Set = ss
+	ss *safeOrderedSet[interface{}]
+	_  OrderedSet = ss
 )
 
-type safeOrderedSet struct {
-	s *unsafeOrderedSet
+type safeOrderedSet[T comparable] struct {
+	s *unsafeOrderedSet[T]
 	sync.RWMutex
+	lockOrder
 }
 
-func newSafeOrderedSet() safeOrderedSet {
-	return safeOrderedSet{s: newUnsafeOrderedSet()}
+func newSafeOrderedSet[T comparable]() safeOrderedSet[T] {
+	return safeOrderedSet[T]{s: newUnsafeOrderedSet[T]()}
 }
 
-func (s *safeOrderedSet) Add(i ...interface{}) {
+func (s *safeOrderedSet[T]) Add(i ...T) {
 	s.Lock()
+	defer s.Unlock()
 	s.s.Add(i...)
-	s.Unlock()
 }
 
-func (s *safeOrderedSet) Contains(i ...interface{}) bool {
+func (s *safeOrderedSet[T]) Contains(i ...T) bool {
 	s.RLock()
-	ret := s.s.Contains(i...)
-	s.RUnlock()
-	return ret
+	defer s.RUnlock()
+	return s.s.Contains(i...)
 }
 
-func (s *safeOrderedSet) Clear() {
+func (s *safeOrderedSet[T]) Clear() {
 	s.Lock()
-	s.s = newUnsafeOrderedSet()
-	s.Unlock()
+	defer s.Unlock()
+	checkFrozen(s.s.frozen)
+	s.s = newUnsafeOrderedSet[T]()
 }
 
-func (s *safeOrderedSet) Remove(i interface{}) {
+func (s *safeOrderedSet[T]) Remove(i T) {
 	s.Lock()
+	defer s.Unlock()
 	s.s.Remove(i)
-	s.Unlock()
 }
 
-func (s *safeOrderedSet) Len() int {
+func (s *safeOrderedSet[T]) Len() int {
 	s.RLock()
 	defer s.RUnlock()
-	return len(s.s.m)
+	return s.s.Len()
+}
+
+func (s *safeOrderedSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *safeOrderedSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
 }
 
-func (s *safeOrderedSet) Iter() <-chan interface{} {
-	ch := make(chan interface{})
-	go func() {
+func (s *safeOrderedSet[T]) Each(fn func(elem T) bool) {
+	s.RLock()
+	defer s.RUnlock()
+	s.s.Each(fn)
+}
+
+func (s *safeOrderedSet[T]) Equal(other Collection[T]) bool {
+	o, ok := other.(*safeOrderedSet[T])
+	if !ok {
+		elems := toSlice(other)
 		s.RLock()
-		for _, key := range s.s.keys {
-			ch <- s.s.m[key]
-		}
-		close(ch)
-		s.RUnlock()
-	}()
+		defer s.RUnlock()
+		return len(elems) == s.s.Len() && contains[T](s.s, elems...)
+	}
 
-	return ch
+	unlock := lockPair(s, o, false)
+	defer unlock()
+	return s.s.Equal(o.s)
 }
 
-func (s *safeOrderedSet) Equal(other Set) bool {
-	o := other.(*safeOrderedSet)
+func (s *safeOrderedSet[T]) Clone() TypedSet[T] {
+	s.RLock()
+	defer s.RUnlock()
+	return &safeOrderedSet[T]{s: s.s.Clone().(*unsafeOrderedSet[T])}
+}
 
+func (s *safeOrderedSet[T]) ToSlice() []T {
 	s.RLock()
-	o.RLock()
+	defer s.RUnlock()
+	return s.s.ToSlice()
+}
+
+func (s *safeOrderedSet[T]) RemoveFrom(other Collection[T]) {
+	o, ok := other.(*safeOrderedSet[T])
+	if !ok {
+		elems := hashable(toSlice(other))
+		s.Lock()
+		defer s.Unlock()
+		checkFrozen(s.s.frozen)
+		for _, elem := range elems {
+			s.s.Remove(elem)
+		}
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.RemoveFrom(o.s)
+}
+
+func (s *safeOrderedSet[T]) AddFrom(other Collection[T]) {
+	o, ok := other.(*safeOrderedSet[T])
+	if !ok {
+		elems := toSlice(other)
+		s.Lock()
+		defer s.Unlock()
+		s.s.Add(elems...)
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.AddFrom(o.s)
+}
+
+func (s *safeOrderedSet[T]) RetainFrom(other Collection[T]) {
+	o, ok := other.(*safeOrderedSet[T])
+	if !ok {
+		keep := newUnsafeSet[T]()
+		keep.Add(hashable(toSlice(other))...)
+		s.Lock()
+		defer s.Unlock()
+		s.s.RetainFrom(keep)
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.RetainFrom(o.s)
+}
+
+func (s *safeOrderedSet[T]) Union(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeOrderedSet[T]).Union)
+}
+
+func (s *safeOrderedSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeOrderedSet[T]).Intersect)
+}
+
+func (s *safeOrderedSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeOrderedSet[T]).Difference)
+}
+
+func (s *safeOrderedSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeOrderedSet[T]).SymmetricDifference)
+}
+
+// combine applies op to the unsafe contents of s and other, holding both
+// read locks when other is a safeOrderedSet, and to an ordered copy of s otherwise,
+// so that op reads other with s unlocked.
+func (s *safeOrderedSet[T]) combine(other Collection[T], op func(*unsafeOrderedSet[T], Collection[T]) TypedSet[T]) TypedSet[T] {
+	if o, ok := other.(*safeOrderedSet[T]); ok {
+		unlock := lockPair(s, o, false)
+		defer unlock()
+		return &safeOrderedSet[T]{s: op(s.s, o.s).(*unsafeOrderedSet[T])}
+	}
 
-	ret := s.s.Equal(o.s)
+	snapshot := newUnsafeOrderedSet[T]()
+	s.RLock()
+	snapshot.AddFrom(s.s)
 	s.RUnlock()
-	o.RUnlock()
-	return ret
+	return &safeOrderedSet[T]{s: op(snapshot, other).(*unsafeOrderedSet[T])}
+}
+
+func (s *safeOrderedSet[T]) IsSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeOrderedSet[T]).IsSubset, isSubset[T])
+}
+
+func (s *safeOrderedSet[T]) IsSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeOrderedSet[T]).IsSuperset, isSuperset[T])
+}
+
+func (s *safeOrderedSet[T]) IsProperSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeOrderedSet[T]).IsProperSubset, isProperSubset[T])
+}
+
+func (s *safeOrderedSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeOrderedSet[T]).IsProperSuperset, isProperSuperset[T])
+}
+
+func (s *safeOrderedSet[T]) IsDisjoint(other Collection[T]) bool {
+	return s.relate(other, (*unsafeOrderedSet[T]).IsDisjoint, isDisjoint[T])
 }
 
-func (s *safeOrderedSet) Clone() Set {
+// relate applies op to the unsafe contents of s and other, holding both
+// read locks, when other is a safeOrderedSet, and otherwise answers with
+// fallback, which reads the two sets one at a time.
+func (s *safeOrderedSet[T]) relate(other Collection[T], op func(*unsafeOrderedSet[T], Collection[T]) bool, fallback func(s, other Collection[T]) bool) bool {
+	if o, ok := other.(*safeOrderedSet[T]); ok {
+		unlock := lockPair(s, o, false)
+		defer unlock()
+		return op(s.s, o.s)
+	}
+
+	return fallback(s, other)
+}
+
+func (s *safeOrderedSet[T]) At(i int) (T, bool) {
 	s.RLock()
+	defer s.RUnlock()
+	return s.s.At(i)
+}
 
-	unsafeClone := s.s.Clone().(*unsafeOrderedSet)
-	ret := &safeOrderedSet{s: unsafeClone}
-	s.RUnlock()
-	return ret
+func (s *safeOrderedSet[T]) IndexOf(elem T) int {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.IndexOf(elem)
 }
 
-func (s *safeOrderedSet) ToSlice() []interface{} {
-	keys := make([]interface{}, 0, s.Len())
+func (s *safeOrderedSet[T]) First() (T, bool) {
 	s.RLock()
-	for _, key := range s.s.keys {
-		keys = append(keys, s.s.m[key])
-	}
-	s.RUnlock()
-	return keys
+	defer s.RUnlock()
+	return s.s.First()
+}
+
+func (s *safeOrderedSet[T]) Last() (T, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Last()
 }
 
-func (s *safeOrderedSet) RemoveFrom(other Set) {
-	o := other.(*safeOrderedSet)
+func (s *safeOrderedSet[T]) PopFirst() (T, bool) {
 	s.Lock()
-	o.RLock()
-	s.s.RemoveFrom(o.s)
-	s.Unlock()
-	o.RUnlock()
+	defer s.Unlock()
+	return s.s.PopFirst()
 }
 
-func (s *safeOrderedSet) AddFrom(other Set) {
-	o := other.(*safeOrderedSet)
+func (s *safeOrderedSet[T]) PopLast() (T, bool) {
 	s.Lock()
-	o.RLock()
-	s.s.AddFrom(o.s)
-	s.Unlock()
-	o.RUnlock()
+	defer s.Unlock()
+	return s.s.PopLast()
 }
 
-func (s *safeOrderedSet) RetainFrom(other Set) {
-	o := other.(*safeOrderedSet)
+func (s *safeOrderedSet[T]) MoveToFront(elem T) bool {
 	s.Lock()
-	o.RLock()
-	s.s.RetainFrom(o.s)
-	s.Unlock()
-	o.RUnlock()
+	defer s.Unlock()
+	return s.s.MoveToFront(elem)
+}
+
+func (s *safeOrderedSet[T]) MoveToBack(elem T) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.s.MoveToBack(elem)
+}
+
+func (s *safeOrderedSet[T]) InsertAt(i int, elem T) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.s.InsertAt(i, elem)
+}
+
+func (s *safeOrderedSet[T]) InsertBefore(mark, elem T) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.s.InsertBefore(mark, elem)
+}
+
+func (s *safeOrderedSet[T]) InsertAfter(mark, elem T) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.s.InsertAfter(mark, elem)
 }
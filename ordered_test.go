@@ -681,3 +681,246 @@ func TestToSliceUnthreadsafeOrdered(t *testing.T) {
 		}
 	}
 }
+
+func TestRemoveUnsafeOrderedSetKeepsOrder(t *testing.T) {
+	a := makeUnsafeOrderedSet([]int{1, 2, 3, 4, 5})
+
+	a.Remove(1)
+	a.Remove(3)
+	a.Remove(5)
+	assertOrder(t, a, 2, 4)
+
+	a.Add(1)
+	a.Remove(2)
+	assertOrder(t, a, 4, 1)
+
+	a.Remove(4)
+	a.Remove(1)
+	a.Add(6)
+	assertOrder(t, a, 6)
+}
+
+// sliceOrderedSet is the slice-backed layout unsafeOrderedSet used before it
+// moved to a linked list, kept to benchmark the two against each other.
+type sliceOrderedSet struct {
+	currentIndex int
+	m            map[int]interface{}
+	index        map[interface{}]int
+	keys         []int
+}
+
+func (s *sliceOrderedSet) Add(item interface{}) {
+	if _, found := s.index[item]; found {
+		return
+	}
+
+	s.keys = append(s.keys, s.currentIndex)
+	s.m[s.currentIndex] = item
+	s.index[item] = s.currentIndex
+	s.currentIndex++
+}
+
+func (s *sliceOrderedSet) Remove(item interface{}) {
+	index, found := s.index[item]
+	if !found {
+		return
+	}
+
+	delete(s.m, index)
+	for i := range s.keys {
+		if s.keys[i] == index {
+			s.keys = append(s.keys[:i], s.keys[i+1:]...)
+			break
+		}
+	}
+
+	delete(s.index, item)
+}
+
+const benchOrderedN = 10000
+
+func BenchmarkUnsafeOrderedSetRemove(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		s := NewUnsafeOrderedSet()
+		for j := 0; j < benchOrderedN; j++ {
+			s.Add(j)
+		}
+		b.StartTimer()
+
+		for j := 0; j < benchOrderedN; j++ {
+			s.Remove(j * 7919 % benchOrderedN)
+		}
+	}
+}
+
+func BenchmarkSliceOrderedSetRemove(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		s := &sliceOrderedSet{m: make(map[int]interface{}), index: make(map[interface{}]int)}
+		for j := 0; j < benchOrderedN; j++ {
+			s.Add(j)
+		}
+		b.StartTimer()
+
+		for j := 0; j < benchOrderedN; j++ {
+			s.Remove(j * 7919 % benchOrderedN)
+		}
+	}
+}
+
+func BenchmarkUnsafeOrderedSetAdd(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s := NewUnsafeOrderedSet()
+		for j := 0; j < benchOrderedN; j++ {
+			s.Add(j)
+		}
+	}
+}
+
+func BenchmarkSliceOrderedSetAdd(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s := &sliceOrderedSet{m: make(map[int]interface{}), index: make(map[interface{}]int)}
+		for j := 0; j < benchOrderedN; j++ {
+			s.Add(j)
+		}
+	}
+}
+
+func TestOrderedSetPositions(t *testing.T) {
+	for _, a := range []OrderedSet{NewOrderedSet("a", "b", "c", "d").(OrderedSet), NewUnsafeOrderedSetFromSlice([]interface{}{"a", "b", "c", "d"}).(OrderedSet)} {
+		for i, want := range []interface{}{"a", "b", "c", "d"} {
+			if got, ok := a.At(i); !ok || got != want {
+				t.Errorf("At(%v): expected %v, got %v", i, want, got)
+			}
+
+			if got := a.IndexOf(want); got != i {
+				t.Errorf("IndexOf(%v): expected %v, got %v", want, i, got)
+			}
+		}
+
+		if _, ok := a.At(-1); ok {
+			t.Error("At(-1) should report that there is no such element")
+		}
+
+		if _, ok := a.At(4); ok {
+			t.Error("At(4) should report that there is no such element")
+		}
+
+		if a.IndexOf("z") != -1 {
+			t.Error("IndexOf should return -1 for missing elements")
+		}
+
+		if first, ok := a.First(); !ok || first != "a" {
+			t.Errorf("First: expected a, got %v", first)
+		}
+
+		if last, ok := a.Last(); !ok || last != "d" {
+			t.Errorf("Last: expected d, got %v", last)
+		}
+	}
+}
+
+func TestOrderedSetPop(t *testing.T) {
+	for _, a := range []OrderedSet{NewOrderedSet().(OrderedSet), NewUnsafeOrderedSet().(OrderedSet)} {
+		if _, ok := a.PopFirst(); ok {
+			t.Error("PopFirst on an empty set should report that there is no element")
+		}
+
+		if _, ok := a.Last(); ok {
+			t.Error("Last on an empty set should report that there is no element")
+		}
+
+		a.Add(1, 2, 3, 4)
+
+		if v, ok := a.PopFirst(); !ok || v != 1 {
+			t.Errorf("PopFirst: expected 1, got %v", v)
+		}
+
+		if v, ok := a.PopLast(); !ok || v != 4 {
+			t.Errorf("PopLast: expected 4, got %v", v)
+		}
+
+		a.Add(1)
+		assertOrder(t, a, 2, 3, 1)
+
+		for a.Len() > 0 {
+			a.PopLast()
+		}
+
+		if _, ok := a.First(); ok {
+			t.Error("First on an emptied set should report that there is no element")
+		}
+	}
+}
+
+func TestOrderedSetMove(t *testing.T) {
+	for _, a := range []OrderedSet{NewOrderedSet(1, 2, 3).(OrderedSet), NewUnsafeOrderedSetFromSlice([]interface{}{1, 2, 3}).(OrderedSet)} {
+		if !a.MoveToFront(3) {
+			t.Error("MoveToFront should report true for an element in the set")
+		}
+		assertOrder(t, a, 3, 1, 2)
+
+		a.MoveToBack(3)
+		assertOrder(t, a, 1, 2, 3)
+
+		a.MoveToFront(1)
+		a.MoveToBack(3)
+		assertOrder(t, a, 1, 2, 3)
+
+		if a.MoveToFront(4) || a.MoveToBack(4) {
+			t.Error("moving a missing element should report false")
+		}
+		assertOrder(t, a, 1, 2, 3)
+	}
+}
+
+func TestOrderedSetInsert(t *testing.T) {
+	for _, a := range []OrderedSet{NewOrderedSet(1, 2, 3).(OrderedSet), NewUnsafeOrderedSetFromSlice([]interface{}{1, 2, 3}).(OrderedSet)} {
+		a.InsertAt(0, 0)
+		a.InsertAt(4, 4)
+		a.InsertAt(2, 9)
+		assertOrder(t, a, 0, 1, 9, 2, 3, 4)
+
+		a.InsertAt(5, 9)
+		assertOrder(t, a, 0, 1, 2, 3, 4, 9)
+
+		if a.InsertAt(7, 7) || a.InsertAt(-1, 7) || a.InsertAt(6, 9) {
+			t.Error("InsertAt out of range should report false")
+		}
+		assertOrder(t, a, 0, 1, 2, 3, 4, 9)
+
+		a.InsertBefore(0, 8)
+		a.InsertAfter(9, 7)
+		a.InsertBefore(3, 9)
+		a.InsertAfter(0, 7)
+		assertOrder(t, a, 8, 0, 7, 1, 2, 9, 3, 4)
+
+		if a.InsertBefore(5, 6) || a.InsertAfter(5, 6) || a.Contains(6) {
+			t.Error("inserting next to a missing mark should report false and not add the element")
+		}
+
+		if !a.InsertAfter(8, 8) {
+			t.Error("inserting an element next to itself should be a no-op that reports true")
+		}
+		assertOrder(t, a, 8, 0, 7, 1, 2, 9, 3, 4)
+	}
+}
+
+func TestOrderedConstructorsReturnSet(t *testing.T) {
+	s := NewOrderedSet(1, 2)
+	for _, ctor := range []func(...interface{}) Set{NewSet, NewOrderedSet} {
+		s = ctor(3)
+		if s.Len() != 1 {
+			t.Errorf("expected {3}, got %v", s)
+		}
+	}
+
+	o, ok := NewOrderedSetFromSlice([]interface{}{1, 2}).(OrderedSet)
+	if !ok {
+		t.Fatal("expected NewOrderedSetFromSlice to return an OrderedSet")
+	}
+	if first, _ := o.First(); first != 1 {
+		t.Errorf("expected 1 first, got %v", first)
+	}
+}
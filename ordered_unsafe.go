@@ -1,38 +1,88 @@
 package set
 
 var (
-	us *unsafeOrderedSet
-	_  Set = us
+	us *unsafeOrderedSet[interface{}]
+	_  OrderedSet = us
 )
 
-type unsafeOrderedSet struct {
-	currentIndex int
-	m            map[int]interface{}
-	index        map[interface{}]int
-	keys         []int
+// unsafeOrderedSet keeps its elements in a doubly linked list, in insertion
+// order, and indexes the list nodes by element so that lookups and removals
+// are O(1).
+type unsafeOrderedSet[T comparable] struct {
+	head   *orderedElement[T]
+	tail   *orderedElement[T]
+	index  map[T]*orderedElement[T]
+	frozen bool
 }
 
-func newUnsafeOrderedSet() *unsafeOrderedSet {
-	return &unsafeOrderedSet{currentIndex: 0, m: make(map[int]interface{}), keys: make([]int, 0), index: make(map[interface{}]int)}
+type orderedElement[T comparable] struct {
+	value T
+	prev  *orderedElement[T]
+	next  *orderedElement[T]
 }
 
-func (s *unsafeOrderedSet) Add(i ...interface{}) {
+func newUnsafeOrderedSet[T comparable]() *unsafeOrderedSet[T] {
+	return &unsafeOrderedSet[T]{index: make(map[T]*orderedElement[T])}
+}
+
+func (s *unsafeOrderedSet[T]) Add(i ...T) {
+	checkFrozen(s.frozen)
 	for _, item := range i {
 		if _, found := s.index[item]; found {
 			continue
 		}
 
-		if _, ok := s.m[s.currentIndex]; !ok {
-			s.keys = append(s.keys, s.currentIndex)
-		}
+		e := &orderedElement[T]{value: item}
+		s.index[item] = e
+		s.pushBack(e)
+	}
+}
 
-		s.m[s.currentIndex] = item
-		s.index[item] = s.currentIndex
-		s.currentIndex++
+// pushBack links e in as the last element of the list.
+func (s *unsafeOrderedSet[T]) pushBack(e *orderedElement[T]) {
+	e.prev, e.next = s.tail, nil
+	if s.tail == nil {
+		s.head = e
+	} else {
+		s.tail.next = e
 	}
+	s.tail = e
 }
 
-func (s *unsafeOrderedSet) Contains(i ...interface{}) bool {
+// linkBefore links e in just before mark, or at the back if mark is nil.
+func (s *unsafeOrderedSet[T]) linkBefore(e, mark *orderedElement[T]) {
+	if mark == nil {
+		s.pushBack(e)
+		return
+	}
+
+	e.prev, e.next = mark.prev, mark
+	if mark.prev == nil {
+		s.head = e
+	} else {
+		mark.prev.next = e
+	}
+	mark.prev = e
+}
+
+// unlink removes e from the list, leaving the index untouched.
+func (s *unsafeOrderedSet[T]) unlink(e *orderedElement[T]) {
+	if e.prev == nil {
+		s.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+
+	if e.next == nil {
+		s.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+
+	e.prev, e.next = nil, nil
+}
+
+func (s *unsafeOrderedSet[T]) Contains(i ...T) bool {
 	for _, item := range i {
 		if _, found := s.index[item]; !found {
 			return false
@@ -41,54 +91,56 @@ func (s *unsafeOrderedSet) Contains(i ...interface{}) bool {
 	return true
 }
 
-func (s *unsafeOrderedSet) Clear() {
-	*s = *newUnsafeOrderedSet()
+func (s *unsafeOrderedSet[T]) Clear() {
+	checkFrozen(s.frozen)
+	*s = *newUnsafeOrderedSet[T]()
 }
 
-func (s *unsafeOrderedSet) Remove(i interface{}) {
-	index, found := s.index[i]
+func (s *unsafeOrderedSet[T]) Remove(i T) {
+	checkFrozen(s.frozen)
+	e, found := s.index[i]
 	if !found {
 		return
 	}
 
-	if _, found := s.m[index]; !found {
-		return
-	}
-
-	delete(s.m, index)
-	for i := range s.keys {
-		if s.keys[i] == index {
-			s.keys = append(s.keys[:i], s.keys[i+1:]...)
-			break
-		}
-	}
-
+	s.unlink(e)
 	delete(s.index, i)
 }
 
-func (s *unsafeOrderedSet) Len() int {
-	return len(s.m)
+func (s *unsafeOrderedSet[T]) Len() int {
+	return len(s.index)
 }
 
-func (s *unsafeOrderedSet) Iter() <-chan interface{} {
-	ch := make(chan interface{})
-	go func() {
-		for _, key := range s.keys {
-			ch <- s.m[key]
-		}
-
-		close(ch)
-	}()
+func (s *unsafeOrderedSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
 
-	return ch
+func (s *unsafeOrderedSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
 }
 
-func (s *unsafeOrderedSet) Equal(other Set) bool {
-	_ = other.(*unsafeOrderedSet)
+func (s *unsafeOrderedSet[T]) Each(fn func(elem T) bool) {
+	for e := s.head; e != nil; e = e.next {
+		if !fn(e.value) {
+			return
+		}
+	}
+}
 
+func (s *unsafeOrderedSet[T]) Equal(other Collection[T]) bool {
 	if s.Len() != other.Len() {
 		return false
 	}
+
+	if o, ok := other.(*unsafeOrderedSet[T]); ok {
+		for elem := range s.index {
+			if _, found := o.index[elem]; !found {
+				return false
+			}
+		}
+		return true
+	}
+
 	for elem := range s.index {
 		if !other.Contains(elem) {
 			return false
@@ -97,26 +149,27 @@ func (s *unsafeOrderedSet) Equal(other Set) bool {
 	return true
 }
 
-func (s *unsafeOrderedSet) Clone() Set {
-	clonedSet := newUnsafeOrderedSet()
-	for _, key := range s.keys {
-		if s.m[key] != nil {
-			clonedSet.Add(s.m[key])
+func (s *unsafeOrderedSet[T]) Clone() TypedSet[T] {
+	clonedSet := newUnsafeOrderedSet[T]()
+	for e := s.head; e != nil; e = e.next {
+		if any(e.value) != nil {
+			clonedSet.Add(e.value)
 		}
 	}
 	return clonedSet
 }
 
-func (s *unsafeOrderedSet) ToSlice() []interface{} {
-	keys := make([]interface{}, 0, s.Len())
-	for _, key := range s.keys {
-		keys = append(keys, s.m[key])
+func (s *unsafeOrderedSet[T]) ToSlice() []T {
+	keys := make([]T, 0, s.Len())
+	for e := s.head; e != nil; e = e.next {
+		keys = append(keys, e.value)
 	}
 
 	return keys
 }
 
-func (s *unsafeOrderedSet) RemoveFrom(other Set) {
+func (s *unsafeOrderedSet[T]) RemoveFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
 	for elem := range s.index {
 		if other.Contains(elem) {
 			s.Remove(elem)
@@ -124,20 +177,260 @@ func (s *unsafeOrderedSet) RemoveFrom(other Set) {
 	}
 }
 
-func (s *unsafeOrderedSet) AddFrom(other Set) {
-	o := other.(*unsafeOrderedSet)
-
-	for elem := range o.index {
-		if !s.Contains(elem) {
-			s.Add(elem)
+func (s *unsafeOrderedSet[T]) AddFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	if o, ok := other.(*unsafeOrderedSet[T]); ok {
+		for e := o.head; e != nil; e = e.next {
+			s.Add(e.value)
 		}
+		return
 	}
+
+	s.Add(toSlice(other)...)
 }
 
-func (s *unsafeOrderedSet) RetainFrom(other Set) {
+func (s *unsafeOrderedSet[T]) RetainFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
 	for elem := range s.index {
 		if !other.Contains(elem) {
 			s.Remove(elem)
 		}
 	}
 }
+
+func (s *unsafeOrderedSet[T]) Union(other Collection[T]) TypedSet[T] {
+	ret := newUnsafeOrderedSet[T]()
+	ret.AddFrom(s)
+	ret.AddFrom(other)
+	return ret
+}
+
+func (s *unsafeOrderedSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	ret := newUnsafeOrderedSet[T]()
+	for e := s.head; e != nil; e = e.next {
+		if other.Contains(e.value) {
+			ret.Add(e.value)
+		}
+	}
+	return ret
+}
+
+func (s *unsafeOrderedSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	ret := newUnsafeOrderedSet[T]()
+	for e := s.head; e != nil; e = e.next {
+		if !other.Contains(e.value) {
+			ret.Add(e.value)
+		}
+	}
+	return ret
+}
+
+func (s *unsafeOrderedSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	ret := s.Difference(other).(*unsafeOrderedSet[T])
+	for _, elem := range toSlice(other) {
+		if _, found := s.index[elem]; !found {
+			ret.Add(elem)
+		}
+	}
+	return ret
+}
+
+func (s *unsafeOrderedSet[T]) IsSubset(other Collection[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+
+	for elem := range s.index {
+		if !other.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeOrderedSet[T]) IsSuperset(other Collection[T]) bool {
+	if s.Len() < other.Len() {
+		return false
+	}
+
+	if o, ok := other.(*unsafeOrderedSet[T]); ok {
+		return o.IsSubset(s)
+	}
+
+	ret := true
+	other.Each(func(elem T) bool {
+		ret = contains[T](s, elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *unsafeOrderedSet[T]) IsProperSubset(other Collection[T]) bool {
+	return s.Len() < other.Len() && s.IsSubset(other)
+}
+
+func (s *unsafeOrderedSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return s.Len() > other.Len() && s.IsSuperset(other)
+}
+
+func (s *unsafeOrderedSet[T]) IsDisjoint(other Collection[T]) bool {
+	for elem := range s.index {
+		if other.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeOrderedSet[T]) At(i int) (T, bool) {
+	return s.value(s.element(i, s.Len()))
+}
+
+// element returns the node at position i of a list holding n nodes, walking
+// from whichever end is closer, or nil if i is out of range.
+func (s *unsafeOrderedSet[T]) element(i, n int) *orderedElement[T] {
+	if i < 0 || i >= n {
+		return nil
+	}
+
+	var e *orderedElement[T]
+	if i < n/2 {
+		for e = s.head; i > 0; i-- {
+			e = e.next
+		}
+	} else {
+		for e = s.tail; i < n-1; i++ {
+			e = e.prev
+		}
+	}
+	return e
+}
+
+func (s *unsafeOrderedSet[T]) IndexOf(elem T) int {
+	if _, found := s.index[elem]; !found {
+		return -1
+	}
+
+	i := 0
+	for e := s.head; e.value != elem; e = e.next {
+		i++
+	}
+	return i
+}
+
+func (s *unsafeOrderedSet[T]) First() (T, bool) {
+	return s.value(s.head)
+}
+
+func (s *unsafeOrderedSet[T]) Last() (T, bool) {
+	return s.value(s.tail)
+}
+
+func (s *unsafeOrderedSet[T]) PopFirst() (T, bool) {
+	checkFrozen(s.frozen)
+	return s.pop(s.head)
+}
+
+func (s *unsafeOrderedSet[T]) PopLast() (T, bool) {
+	checkFrozen(s.frozen)
+	return s.pop(s.tail)
+}
+
+func (s *unsafeOrderedSet[T]) value(e *orderedElement[T]) (T, bool) {
+	if e == nil {
+		var zero T
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (s *unsafeOrderedSet[T]) pop(e *orderedElement[T]) (T, bool) {
+	if e == nil {
+		var zero T
+		return zero, false
+	}
+
+	s.unlink(e)
+	delete(s.index, e.value)
+	return e.value, true
+}
+
+func (s *unsafeOrderedSet[T]) MoveToFront(elem T) bool {
+	checkFrozen(s.frozen)
+	e, found := s.index[elem]
+	if !found {
+		return false
+	}
+
+	s.unlink(e)
+	s.linkBefore(e, s.head)
+	return true
+}
+
+func (s *unsafeOrderedSet[T]) MoveToBack(elem T) bool {
+	checkFrozen(s.frozen)
+	e, found := s.index[elem]
+	if !found {
+		return false
+	}
+
+	s.unlink(e)
+	s.pushBack(e)
+	return true
+}
+
+func (s *unsafeOrderedSet[T]) InsertAt(i int, elem T) bool {
+	checkFrozen(s.frozen)
+	n := s.Len()
+	if _, found := s.index[elem]; found {
+		n--
+	}
+
+	if i < 0 || i > n {
+		return false
+	}
+
+	e := s.detach(elem)
+	s.linkBefore(e, s.element(i, n))
+	return true
+}
+
+func (s *unsafeOrderedSet[T]) InsertBefore(mark, elem T) bool {
+	checkFrozen(s.frozen)
+	if _, found := s.index[mark]; !found {
+		return false
+	} else if mark == elem {
+		return true
+	}
+
+	e := s.detach(elem)
+	s.linkBefore(e, s.index[mark])
+	return true
+}
+
+func (s *unsafeOrderedSet[T]) InsertAfter(mark, elem T) bool {
+	checkFrozen(s.frozen)
+	if _, found := s.index[mark]; !found {
+		return false
+	} else if mark == elem {
+		return true
+	}
+
+	e := s.detach(elem)
+	s.linkBefore(e, s.index[mark].next)
+	return true
+}
+
+// detach returns the node for elem, unlinked from the list but present in
+// the index, creating it if elem is not yet in the set.
+func (s *unsafeOrderedSet[T]) detach(elem T) *orderedElement[T] {
+	e, found := s.index[elem]
+	if found {
+		s.unlink(e)
+		return e
+	}
+
+	e = &orderedElement[T]{value: elem}
+	s.index[elem] = e
+	return e
+}
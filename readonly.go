@@ -0,0 +1,94 @@
+package set
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	ros *readOnlySet[interface{}]
+	_   ReadOnlySet = ros
+	_   ReadOnlySet = Set(nil)
+)
+
+// TypedReadOnlySet is the reading half of a TypedSet. Every TypedSet is also
+// a TypedReadOnlySet, so functions that only read can accept either.
+type TypedReadOnlySet[T any] interface {
+	Collection[T]
+
+	TryContains(i ...T) (bool, error)
+	Iter() <-chan T
+	Iterator() *Iterator[T]
+	ToSlice() []T
+	Equal(other Collection[T]) bool
+
+	// Clone returns a mutable copy of the set.
+	Clone() TypedSet[T]
+}
+
+// ReadOnlySet is the untyped read-only set. It is the same type as
+// TypedReadOnlySet[interface{}].
+type ReadOnlySet = TypedReadOnlySet[interface{}]
+
+// readOnlySet hides the mutators of the set it wraps. It shares the wrapped
+// set's storage, so it sees later changes made through that set.
+type readOnlySet[T any] struct {
+	s TypedSet[T]
+}
+
+// AsReadOnly returns a view of s that can read it but not change it, and
+// that cannot be converted back to a TypedSet. Changes made to s through
+// other references show through the view; Freeze s as well if it must not
+// change at all.
+func AsReadOnly[T any](s TypedSet[T]) TypedReadOnlySet[T] {
+	return &readOnlySet[T]{s: s}
+}
+
+func (r *readOnlySet[T]) Len() int {
+	return r.s.Len()
+}
+
+func (r *readOnlySet[T]) Contains(i ...T) bool {
+	return r.s.Contains(i...)
+}
+
+func (r *readOnlySet[T]) TryContains(i ...T) (bool, error) {
+	return r.s.TryContains(i...)
+}
+
+func (r *readOnlySet[T]) Iter() <-chan T {
+	return r.s.Iter()
+}
+
+func (r *readOnlySet[T]) Iterator() *Iterator[T] {
+	return r.s.Iterator()
+}
+
+func (r *readOnlySet[T]) Each(fn func(elem T) bool) {
+	r.s.Each(fn)
+}
+
+func (r *readOnlySet[T]) ToSlice() []T {
+	return r.s.ToSlice()
+}
+
+func (r *readOnlySet[T]) Equal(other Collection[T]) bool {
+	return r.s.Equal(other)
+}
+
+func (r *readOnlySet[T]) Clone() TypedSet[T] {
+	return r.s.Clone()
+}
+
+func (r *readOnlySet[T]) String() string {
+	return fmt.Sprint(r)
+}
+
+// Format prints the view like the set it wraps.
+func (r *readOnlySet[T]) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, fmt.FormatString(f, verb), r.s)
+}
+
+func (r *readOnlySet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.s)
+}
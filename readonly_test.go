@@ -0,0 +1,70 @@
+package set
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestAsReadOnly(t *testing.T) {
+	for name, a := range constructors {
+		s := a(1, 2, 3)
+		r := AsReadOnly(s)
+
+		if r.Len() != 3 || !r.Contains(1, 2, 3) || !r.Equal(a(1, 2, 3)) || len(r.ToSlice()) != 3 {
+			t.Errorf("%v: expected the view to read {1, 2, 3}, got %v", name, r)
+		}
+		if _, ok := r.(Set); ok {
+			t.Errorf("%v: a read-only view should not be a Set", name)
+		}
+		if _, ok := r.(Mutable[interface{}]); ok {
+			t.Errorf("%v: a read-only view should not be Mutable", name)
+		}
+
+		s.Add(4)
+		if !r.Contains(4) || r.Len() != 4 {
+			t.Errorf("%v: expected the view to see changes to the set, got %v", name, r)
+		}
+
+		n := 0
+		for range r.Iter() {
+			n++
+		}
+		if n != 4 {
+			t.Errorf("%v: expected to iterate over 4 elements, got %v", name, n)
+		}
+
+		c := r.Clone()
+		c.Add(5)
+		if r.Contains(5) || s.Contains(5) {
+			t.Errorf("%v: changing a clone of the view should not change the set", name)
+		}
+	}
+}
+
+func TestAsReadOnlyPrints(t *testing.T) {
+	s := NewOrderedSet(2, 1)
+	r := AsReadOnly[interface{}](s)
+
+	if got, want := fmt.Sprint(r), fmt.Sprint(s); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := fmt.Sprintf("%#v", r), fmt.Sprintf("%#v", s); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "[2,1]" {
+		t.Errorf("expected [2,1], got %s", b)
+	}
+}
+
+func TestSetIsReadOnlySet(t *testing.T) {
+	var r ReadOnlySet = NewSet(1, 2)
+	if !r.Contains(1, 2) {
+		t.Errorf("expected a Set to work as a ReadOnlySet")
+	}
+}
@@ -2,9 +2,11 @@ package set
 
 import (
 	"math/rand"
+	"reflect"
 	"runtime"
 	"sync"
 	"testing"
+	"time"
 )
 
 const N = 1000
@@ -287,3 +289,249 @@ func TestToSliceDeadlock(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestSelfOperations(t *testing.T) {
+	s := NewSet(1, 2, 3)
+	s.AddFrom(s)
+	s.RetainFrom(s)
+	if !s.Equal(s) || s.Len() != 3 {
+		t.Errorf("Expected {1, 2, 3}; got %v", s.ToSlice())
+	}
+
+	s.RemoveFrom(s)
+	if s.Len() != 0 {
+		t.Errorf("Expected an empty set; got %v", s.ToSlice())
+	}
+}
+
+func TestRemoveMissingConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewSet()
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for i := 0; i < N; i++ {
+		go func(i int) {
+			s.Remove(i)
+			s.Add(i)
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Len() != N {
+		t.Errorf("Expected Len %v; got %v", N, s.Len())
+	}
+}
+
+func TestIndependentSetsConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	a, b := NewSet(), NewSet()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		for i := 0; i < N; i++ {
+			a.Add(i)
+		}
+		wg.Done()
+	}()
+	go func() {
+		for i := 0; i < N; i++ {
+			b.Add(i)
+			b.Remove(i)
+		}
+		wg.Done()
+	}()
+	wg.Wait()
+
+	if a.Len() != N || b.Len() != 0 {
+		t.Errorf("Expected Lens %v and 0; got %v and %v", N, a.Len(), b.Len())
+	}
+}
+
+func BenchmarkIndependentSetsParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		s := NewSet()
+		i := 0
+		for pb.Next() {
+			s.Add(i % N)
+			s.Contains(i % N)
+			i++
+		}
+	})
+}
+
+func BenchmarkSharedSetParallel(b *testing.B) {
+	s := NewSet()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			s.Add(i % N)
+			s.Contains(i % N)
+			i++
+		}
+	})
+}
+
+// poison is an element that makes the comparator or hash of the sets built by
+// panickySets panic, and that map backed sets can't hash.
+var poison = []int{-1}
+
+func panickySets() map[string]Set {
+	isPoison := func(v interface{}) bool {
+		_, ok := v.([]int)
+		return ok
+	}
+	compare := func(a, b interface{}) int {
+		if isPoison(a) || isPoison(b) {
+			panic("poisoned comparator")
+		}
+		return compareAny(a, b)
+	}
+	hash := func(v interface{}) uint64 {
+		if isPoison(v) {
+			panic("poisoned hash")
+		}
+		return hashAny(v)
+	}
+
+	return map[string]Set{
+		"Set":        NewSet(),
+		"OrderedSet": NewOrderedSet(),
+		"SortedSet":  NewSortedSet(compare),
+		"HashSet":    NewHashSet(hash, reflect.DeepEqual),
+	}
+}
+
+// mustPanic runs fn and reports whether it panicked.
+func mustPanic(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+func TestPanicReleasesLock(t *testing.T) {
+	for name, s := range panickySets() {
+		s.Add(1, 2, 3)
+		other := NewUnsafeSetFromSlice([]interface{}{2})
+
+		for op, fn := range map[string]func(){
+			"Add":      func() { s.Add(4, poison) },
+			"Contains": func() { s.Contains(poison) },
+			"Remove":   func() { s.Remove(poison) },
+			"Each":     func() { s.Each(func(interface{}) bool { panic("callback") }) },
+			"Union": func() {
+				s.Union(NewHashSet(hashAny, reflect.DeepEqual, poison))
+			},
+			"AddFrom": func() {
+				s.AddFrom(NewHashSet(hashAny, reflect.DeepEqual, poison))
+			},
+		} {
+			if !mustPanic(fn) {
+				t.Errorf("%v.%v: expected a panic", name, op)
+			}
+
+			done := make(chan struct{})
+			go func() {
+				s.Add(5)
+				s.Remove(5)
+				s.RetainFrom(s.Union(other))
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatalf("%v is still locked after a panic in %v", name, op)
+			}
+		}
+	}
+}
+
+func TestPanicConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	for name, s := range panickySets() {
+		var wg sync.WaitGroup
+		wg.Add(4)
+		for w := 0; w < 4; w++ {
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < N/10; i++ {
+					switch (w + i) % 4 {
+					case 0:
+						mustPanic(func() { s.Add(i, poison) })
+					case 1:
+						mustPanic(func() { s.Each(func(interface{}) bool { panic("callback") }) })
+					case 2:
+						s.Add(i)
+					case 3:
+						s.Remove(i - 1)
+						s.Contains(i)
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		if mustPanic(func() { s.Clear() }) || s.Len() != 0 {
+			t.Errorf("%v: expected a usable empty set after Clear", name)
+		}
+	}
+}
+
+func TestTwoSetOperationsConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(4)
+
+	for _, name := range []string{"Set", "OrderedSet", "SortedSet", "HashSet", "ShardedSet", "CopyOnWriteSet"} {
+		a, b := constructors[name](), constructors[name]()
+		ops := []func(x, y Set){
+			func(x, y Set) { x.AddFrom(y) },
+			func(x, y Set) { x.RemoveFrom(y) },
+			func(x, y Set) { x.RetainFrom(y) },
+			func(x, y Set) { x.Equal(y) },
+			func(x, y Set) { x.Union(y) },
+			func(x, y Set) { x.SymmetricDifference(y) },
+			func(x, y Set) { x.IsSubset(y) },
+			func(x, y Set) { x.IsDisjoint(y) },
+		}
+
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				r := rand.New(rand.NewSource(int64(w)))
+				for i := 0; i < N; i++ {
+					x, y := a, b
+					switch r.Intn(3) {
+					case 0:
+						x, y = b, a
+					case 1:
+						y = x
+					}
+
+					x.Add(r.Intn(20))
+					ops[r.Intn(len(ops))](x, y)
+				}
+			}(w)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(30 * time.Second):
+			t.Fatalf("%v: two-set operations deadlocked", name)
+		}
+	}
+}
@@ -1,26 +1,144 @@
 package set
 
-type Set interface {
-	Add(i ...interface{})
+import "cmp"
+
+// Lener is implemented by anything with a number of elements.
+type Lener interface {
 	Len() int
+}
+
+// Container reports whether elements are members.
+type Container[T any] interface {
+	Contains(i ...T) bool
+}
+
+// Iterable walks over every element.
+type Iterable[T any] interface {
+	// Each calls fn for every element until fn returns false. Safe sets hold
+	// their read lock while fn runs, so fn must not call any method of the
+	// same set, not even one that only reads it: Contains, Len or String
+	// take the read lock again, which deadlocks as soon as a writer is
+	// waiting for it.
+	Each(fn func(elem T) bool)
+}
+
+// Collection is what the operations taking another set need from it. It is
+// built only from capability interfaces, so types outside the package can
+// take part in AddFrom, Equal, Union and the rest by implementing Len,
+// Contains and Each, without implementing TypedSet. Every TypedSet is a
+// Collection.
+type Collection[T any] interface {
+	Lener
+	Container[T]
+	Iterable[T]
+}
+
+// Mutable changes its elements in place.
+type Mutable[T any] interface {
+	Add(i ...T)
+
+	// TryAdd is like Add but returns an ErrUnhashable, and adds nothing,
+	// when one of the elements cannot be used as a map key. Add panics on
+	// such elements.
+	TryAdd(i ...T) error
+	Remove(i T)
 	Clear()
-	Contains(i ...interface{}) bool
-	Equal(other Set) bool
-	Iter() <-chan interface{}
-	Remove(i interface{})
-	ToSlice() []interface{}
-	RemoveFrom(other Set)
-	AddFrom(other Set)
-	RetainFrom(other Set)
-	Clone() Set
+	RemoveFrom(other Collection[T])
+	AddFrom(other Collection[T])
+	RetainFrom(other Collection[T])
 }
 
+// Freezer can be made permanently read-only. Every mutable set in the
+// package is a Freezer, but TypedSet doesn't include it, so that sets
+// implemented elsewhere don't have to; type-assert for it instead:
+//
+//	if f, ok := s.(set.Freezer); ok {
+//		f.Freeze()
+//	}
+type Freezer interface {
+	// Freeze makes every later call that would change the set panic with
+	// ErrFrozen, even if it would change nothing, and makes TryAdd and
+	// decoding return it. Reads, Clone and the set algebra keep working,
+	// and the sets they return are not frozen. A frozen set cannot be
+	// thawed.
+	Freeze()
+	Frozen() bool
+}
+
+// TypedSet is a set holding elements of type T. T only has to be comparable
+// for the map backed sets; hash sets accept any T. See the package
+// documentation for how TypedSet may change between versions.
+type TypedSet[T any] interface {
+	Lener
+	Container[T]
+	Iterable[T]
+	Mutable[T]
+
+	// TryContains is like Contains but returns an ErrUnhashable instead of
+	// panicking when one of the elements cannot be used as a map key.
+	TryContains(i ...T) (bool, error)
+	Iter() <-chan T
+	Iterator() *Iterator[T]
+	ToSlice() []T
+
+	Equal(other Collection[T]) bool
+	Clone() TypedSet[T]
+
+	// Union, Intersect, Difference and SymmetricDifference return a new set
+	// of the same kind as the receiver and leave both operands unchanged.
+	// Ordered results list the receiver's elements first, in insertion
+	// order, followed by the other set's elements in its iteration order.
+	Union(other Collection[T]) TypedSet[T]
+	Intersect(other Collection[T]) TypedSet[T]
+	Difference(other Collection[T]) TypedSet[T]
+	SymmetricDifference(other Collection[T]) TypedSet[T]
+
+	IsSubset(other Collection[T]) bool
+	IsSuperset(other Collection[T]) bool
+	IsProperSubset(other Collection[T]) bool
+	IsProperSuperset(other Collection[T]) bool
+	IsDisjoint(other Collection[T]) bool
+}
+
+// Set is the untyped set. It is the same type as TypedSet[interface{}].
+type Set = TypedSet[interface{}]
+
+// TypedOrderedSet is a TypedSet that keeps its elements in insertion order
+// and gives positional access to them. Positions count from 0 at the oldest
+// element; At and IndexOf walk the set and are O(n).
+type TypedOrderedSet[T comparable] interface {
+	TypedSet[T]
+
+	At(i int) (T, bool)
+	IndexOf(elem T) int
+	First() (T, bool)
+	Last() (T, bool)
+	PopFirst() (T, bool)
+	PopLast() (T, bool)
+
+	// MoveToFront and MoveToBack report false if elem is not in the set.
+	MoveToFront(elem T) bool
+	MoveToBack(elem T) bool
+
+	// InsertAt, InsertBefore and InsertAfter add elem at the given position,
+	// moving it there if it is already in the set. They report false and
+	// leave the set unchanged if i is out of range or mark is missing.
+	InsertAt(i int, elem T) bool
+	InsertBefore(mark, elem T) bool
+	InsertAfter(mark, elem T) bool
+}
+
+// OrderedSet is the untyped ordered set. It is the same type as
+// TypedOrderedSet[interface{}].
+type OrderedSet = TypedOrderedSet[interface{}]
+
+// NewOrderedSet returns a thread safe ordered set holding s. It returns a
+// Set, as it did before OrderedSet existed, so type-assert the result to
+// OrderedSet for positional access, or call NewTypedOrderedSet[interface{}],
+// which returns an OrderedSet. The same goes for NewOrderedSetFromSlice,
+// NewUnsafeOrderedSet and NewUnsafeOrderedSetFromSlice.
 func NewOrderedSet(s ...interface{}) Set {
-	set := newSafeOrderedSet()
-	for _, item := range s {
-		set.Add(item)
-	}
-	return &set
+	return NewTypedOrderedSet(s...)
 }
 
 func NewOrderedSetFromSlice(s []interface{}) Set {
@@ -29,38 +147,293 @@ func NewOrderedSetFromSlice(s []interface{}) Set {
 }
 
 func NewUnsafeOrderedSet() Set {
-	return newUnsafeOrderedSet()
+	return NewTypedUnsafeOrderedSet[interface{}]()
 }
 
 func NewUnsafeOrderedSetFromSlice(s []interface{}) Set {
-	a := NewUnsafeOrderedSet()
+	return NewTypedUnsafeOrderedSetFromSlice(s)
+}
+
+// TypedSortedSet is a TypedSet that keeps its elements sorted by a
+// comparator, which returns a negative number, zero or a positive number
+// when a is less than, equal to or greater than b. Elements the comparator
+// finds equal are the same member of the set. Iteration, Each and ToSlice
+// follow the sort order.
+type TypedSortedSet[T any] interface {
+	TypedSet[T]
+
+	Min() (T, bool)
+	Max() (T, bool)
+
+	// Floor and Ceiling return the greatest element less than or equal to,
+	// and the least element greater than or equal to, elem. Lower and Higher
+	// are their strict counterparts.
+	Floor(elem T) (T, bool)
+	Ceiling(elem T) (T, bool)
+	Lower(elem T) (T, bool)
+	Higher(elem T) (T, bool)
+
+	// SubSet, HeadSet and TailSet return a new sorted set of the same kind
+	// holding the elements between lo and hi, below hi, or above lo. They
+	// are copies, not views: later changes to either set are not reflected
+	// in the other. Copying k elements costs O(log n + k log k).
+	SubSet(lo, hi T, inclusiveLo, inclusiveHi bool) TypedSortedSet[T]
+	HeadSet(hi T, inclusive bool) TypedSortedSet[T]
+	TailSet(lo T, inclusive bool) TypedSortedSet[T]
+
+	// RangeIterator iterates over a snapshot of the elements between lo and
+	// hi in sort order, without copying the rest of the set.
+	RangeIterator(lo, hi T, inclusiveLo, inclusiveHi bool) *Iterator[T]
+
+	// Rank returns the number of elements less than elem, and Select the
+	// element with rank k. CountRange returns the number of elements in
+	// [lo, hi). All three are O(log n).
+	Rank(elem T) int
+	Select(k int) (T, bool)
+	CountRange(lo, hi T) int
+}
+
+// SortedSet is the untyped sorted set. It is the same type as
+// TypedSortedSet[interface{}].
+type SortedSet = TypedSortedSet[interface{}]
+
+func NewSortedSet(compare func(a, b interface{}) int, s ...interface{}) SortedSet {
+	return NewTypedSortedSetFunc(compare, s...)
+}
+
+func NewSortedSetFromSlice(compare func(a, b interface{}) int, s []interface{}) SortedSet {
+	a := NewSortedSet(compare, s...)
+	return a
+}
+
+func NewUnsafeSortedSet(compare func(a, b interface{}) int) SortedSet {
+	return NewTypedUnsafeSortedSetFunc(compare)
+}
+
+func NewUnsafeSortedSetFromSlice(compare func(a, b interface{}) int, s []interface{}) SortedSet {
+	return NewTypedUnsafeSortedSetFuncFromSlice(compare, s)
+}
+
+func NewSet(s ...interface{}) Set {
+	return NewTypedSet(s...)
+}
+
+func NewSetFromSlice(s []interface{}) Set {
+	a := NewSet(s...)
+	return a
+}
+
+func NewUnsafeSet() Set {
+	return NewTypedUnsafeSet[interface{}]()
+}
+
+func NewUnsafeSetFromSlice(s []interface{}) Set {
+	return NewTypedUnsafeSetFromSlice(s)
+}
+
+// NewHashSet returns a thread safe set that buckets elements by hash and
+// tells them apart with equal instead of ==, so it can hold slices, maps and
+// other elements a map cannot. Elements that are equal must hash the same.
+func NewHashSet(hash func(elem interface{}) uint64, equal func(a, b interface{}) bool, s ...interface{}) Set {
+	return NewTypedHashSet(hash, equal, s...)
+}
+
+func NewHashSetFromSlice(hash func(elem interface{}) uint64, equal func(a, b interface{}) bool, s []interface{}) Set {
+	a := NewHashSet(hash, equal, s...)
+	return a
+}
+
+func NewUnsafeHashSet(hash func(elem interface{}) uint64, equal func(a, b interface{}) bool) Set {
+	return NewTypedUnsafeHashSet(hash, equal)
+}
+
+func NewUnsafeHashSetFromSlice(hash func(elem interface{}) uint64, equal func(a, b interface{}) bool, s []interface{}) Set {
+	return NewTypedUnsafeHashSetFromSlice(hash, equal, s)
+}
+
+// NewShardedSet is the untyped NewTypedShardedSet.
+func NewShardedSet(shards int, s ...interface{}) Set {
+	return NewTypedShardedSet(shards, s...)
+}
+
+func NewShardedSetFromSlice(shards int, s []interface{}) Set {
+	a := NewShardedSet(shards, s...)
+	return a
+}
+
+// NewCopyOnWriteSet is the untyped NewTypedCopyOnWriteSet.
+func NewCopyOnWriteSet(s ...interface{}) Set {
+	return NewTypedCopyOnWriteSet(s...)
+}
+
+func NewCopyOnWriteSetFromSlice(s []interface{}) Set {
+	a := NewCopyOnWriteSet(s...)
+	return a
+}
+
+func NewImmutableSet(s ...interface{}) ImmutableSet {
+	return NewTypedImmutableSet(s...)
+}
+
+func NewImmutableSetFromSlice(s []interface{}) ImmutableSet {
+	a := NewImmutableSet(s...)
+	return a
+}
+
+func NewImmutableSetFromSet(s Set) ImmutableSet {
+	return NewTypedImmutableSetFromSet(s)
+}
+
+func NewTypedOrderedSet[T comparable](s ...T) TypedOrderedSet[T] {
+	set := newSafeOrderedSet[T]()
+	for _, item := range s {
+		set.Add(item)
+	}
+	return &set
+}
+
+func NewTypedOrderedSetFromSlice[T comparable](s []T) TypedOrderedSet[T] {
+	a := NewTypedOrderedSet(s...)
+	return a
+}
+
+func NewTypedUnsafeOrderedSet[T comparable]() TypedOrderedSet[T] {
+	return newUnsafeOrderedSet[T]()
+}
+
+func NewTypedUnsafeOrderedSetFromSlice[T comparable](s []T) TypedOrderedSet[T] {
+	a := NewTypedUnsafeOrderedSet[T]()
 	for _, item := range s {
 		a.Add(item)
 	}
 	return a
 }
 
-func NewSet(s ...interface{}) Set {
-	set := newSafeSet()
+func NewTypedSet[T comparable](s ...T) TypedSet[T] {
+	set := newSafeSet[T]()
 	for _, item := range s {
 		set.Add(item)
 	}
-	return set
+	return &set
 }
 
-func NewSetFromSlice(s []interface{}) Set {
-	a := NewSet(s...)
+func NewTypedSetFromSlice[T comparable](s []T) TypedSet[T] {
+	a := NewTypedSet(s...)
 	return a
 }
 
-func NewUnsafeSet() Set {
-	return newUnsafeSet()
+func NewTypedUnsafeSet[T comparable]() TypedSet[T] {
+	return newUnsafeSet[T]()
 }
 
-func NewUnsafeSetFromSlice(s []interface{}) Set {
-	a := NewUnsafeSet()
+func NewTypedUnsafeSetFromSlice[T comparable](s []T) TypedSet[T] {
+	a := NewTypedUnsafeSet[T]()
 	for _, item := range s {
 		a.Add(item)
 	}
 	return a
 }
+
+func NewTypedHashSet[T any](hash func(elem T) uint64, equal func(a, b T) bool, s ...T) TypedSet[T] {
+	set := newSafeHashSet(hash, equal)
+	set.Add(s...)
+	return &set
+}
+
+func NewTypedHashSetFromSlice[T any](hash func(elem T) uint64, equal func(a, b T) bool, s []T) TypedSet[T] {
+	a := NewTypedHashSet(hash, equal, s...)
+	return a
+}
+
+func NewTypedUnsafeHashSet[T any](hash func(elem T) uint64, equal func(a, b T) bool) TypedSet[T] {
+	return newUnsafeHashSet(hash, equal)
+}
+
+func NewTypedUnsafeHashSetFromSlice[T any](hash func(elem T) uint64, equal func(a, b T) bool, s []T) TypedSet[T] {
+	a := NewTypedUnsafeHashSet(hash, equal)
+	a.Add(s...)
+	return a
+}
+
+// NewTypedShardedSet returns a thread safe set that spreads its elements
+// over the given number of independently locked shards, or DefaultShards if
+// shards is below 1. It scales better than NewTypedSet when many goroutines
+// add and look up elements at the same time.
+func NewTypedShardedSet[T comparable](shards int, s ...T) TypedSet[T] {
+	set := newShardedSet[T](shards)
+	set.Add(s...)
+	return set
+}
+
+func NewTypedShardedSetFromSlice[T comparable](shards int, s []T) TypedSet[T] {
+	a := NewTypedShardedSet(shards, s...)
+	return a
+}
+
+// NewTypedCopyOnWriteSet returns a thread safe set for data that is read
+// far more often than it is written. Reads never block; every write copies
+// the whole set, so changes should be batched into as few calls as
+// possible.
+func NewTypedCopyOnWriteSet[T comparable](s ...T) TypedSet[T] {
+	set := newCowSet[T]()
+	set.Add(s...)
+	return set
+}
+
+func NewTypedCopyOnWriteSetFromSlice[T comparable](s []T) TypedSet[T] {
+	a := NewTypedCopyOnWriteSet(s...)
+	return a
+}
+
+func NewTypedImmutableSet[T comparable](s ...T) TypedImmutableSet[T] {
+	return newImmutableSet[T]().with(s...)
+}
+
+func NewTypedImmutableSetFromSlice[T comparable](s []T) TypedImmutableSet[T] {
+	a := NewTypedImmutableSet(s...)
+	return a
+}
+
+// NewTypedImmutableSetFromSet returns an immutable set holding the elements
+// of s. Use ToSet to go the other way.
+func NewTypedImmutableSetFromSet[T comparable](s TypedSet[T]) TypedImmutableSet[T] {
+	return NewTypedImmutableSet(s.ToSlice()...)
+}
+
+func NewTypedSortedSet[T cmp.Ordered](s ...T) TypedSortedSet[T] {
+	return NewTypedSortedSetFunc(cmp.Compare[T], s...)
+}
+
+func NewTypedSortedSetFromSlice[T cmp.Ordered](s []T) TypedSortedSet[T] {
+	a := NewTypedSortedSet(s...)
+	return a
+}
+
+func NewTypedSortedSetFunc[T any](compare func(a, b T) int, s ...T) TypedSortedSet[T] {
+	set := newSafeSortedSet(compare)
+	set.Add(s...)
+	return &set
+}
+
+func NewTypedSortedSetFuncFromSlice[T any](compare func(a, b T) int, s []T) TypedSortedSet[T] {
+	a := NewTypedSortedSetFunc(compare, s...)
+	return a
+}
+
+func NewTypedUnsafeSortedSet[T cmp.Ordered]() TypedSortedSet[T] {
+	return NewTypedUnsafeSortedSetFunc(cmp.Compare[T])
+}
+
+func NewTypedUnsafeSortedSetFromSlice[T cmp.Ordered](s []T) TypedSortedSet[T] {
+	return NewTypedUnsafeSortedSetFuncFromSlice(cmp.Compare[T], s)
+}
+
+func NewTypedUnsafeSortedSetFunc[T any](compare func(a, b T) int) TypedSortedSet[T] {
+	return newUnsafeSortedSet(compare)
+}
+
+func NewTypedUnsafeSortedSetFuncFromSlice[T any](compare func(a, b T) int, s []T) TypedSortedSet[T] {
+	a := NewTypedUnsafeSortedSetFunc(compare)
+	a.Add(s...)
+	return a
+}
@@ -1,6 +1,9 @@
 package set
 
-import "testing"
+import (
+	"math/rand"
+	"testing"
+)
 
 func makeSet(ints []int) Set {
 	set := NewSet()
@@ -689,3 +692,22 @@ func TestToSliceUnthreadsafe(t *testing.T) {
 		j++
 	}
 }
+
+func BenchmarkIter(b *testing.B) {
+	s := makeSet(rand.Perm(N))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range s.Iter() {
+		}
+	}
+}
+
+func BenchmarkEach(b *testing.B) {
+	s := makeSet(rand.Perm(N))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Each(func(interface{}) bool {
+			return true
+		})
+	}
+}
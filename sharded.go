@@ -0,0 +1,360 @@
+package set
+
+import (
+	"hash/maphash"
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	shd *shardedSet[interface{}]
+	_   Set = shd
+)
+
+// DefaultShards is the number of shards used when a sharded set is created
+// with a shard count below 1.
+const DefaultShards = 32
+
+// shard is one independently locked part of a shardedSet. It is padded to a
+// cache line so that neighbouring shards don't contend on the same line.
+type shard[T comparable] struct {
+	s *unsafeSet[T]
+	sync.RWMutex
+	_ [32]byte
+}
+
+// shardedSet spreads its elements over shards by hash, so that goroutines
+// working on different elements rarely wait for each other. Operations on
+// a single element lock one shard; operations on several elements, and
+// those reading the whole set, lock every shard in order and so see and
+// make consistent changes. frozen only changes with every shard locked, so
+// holding any shard lock is enough to rely on it.
+type shardedSet[T comparable] struct {
+	seed   maphash.Seed
+	shards []shard[T]
+	frozen atomic.Bool
+}
+
+func newShardedSet[T comparable](shards int) *shardedSet[T] {
+	if shards < 1 {
+		shards = DefaultShards
+	}
+
+	return &shardedSet[T]{seed: maphash.MakeSeed(), shards: makeShards[T](shards)}
+}
+
+func makeShards[T comparable](n int) []shard[T] {
+	shards := make([]shard[T], n)
+	for i := range shards {
+		shards[i].s = newUnsafeSet[T]()
+	}
+	return shards
+}
+
+// empty returns a set with no elements and the same shard layout as s.
+func (s *shardedSet[T]) empty() *shardedSet[T] {
+	return &shardedSet[T]{seed: s.seed, shards: makeShards[T](len(s.shards))}
+}
+
+// replace swaps the contents of s for elems, giving a zero shardedSet, such
+// as one made by encoding/gob, the default shards first. It returns an
+// ErrUnhashable, and changes nothing, if an element cannot be hashed.
+func (s *shardedSet[T]) replace(elems []T) error {
+	if err := checkHashable(elems); err != nil {
+		return err
+	}
+	if len(s.shards) == 0 {
+		s.seed, s.shards = maphash.MakeSeed(), makeShards[T](DefaultShards)
+	}
+
+	unlock := s.lockAll()
+	defer unlock()
+	if s.frozen.Load() {
+		return ErrFrozen
+	}
+	for i := range s.shards {
+		s.shards[i].s = newUnsafeSet[T]()
+	}
+	s.add(elems...)
+	return nil
+}
+
+func (s *shardedSet[T]) shardFor(elem T) *shard[T] {
+	return &s.shards[hashComparable(s.seed, elem)%uint64(len(s.shards))]
+}
+
+// lockAll write locks every shard in order and returns the function that
+// unlocks them.
+func (s *shardedSet[T]) lockAll() func() {
+	for i := range s.shards {
+		s.shards[i].Lock()
+	}
+	return func() {
+		for i := range s.shards {
+			s.shards[i].Unlock()
+		}
+	}
+}
+
+// rlockAll is like lockAll for read locks.
+func (s *shardedSet[T]) rlockAll() func() {
+	for i := range s.shards {
+		s.shards[i].RLock()
+	}
+	return func() {
+		for i := range s.shards {
+			s.shards[i].RUnlock()
+		}
+	}
+}
+
+// add and remove work on the shards without locking them.
+func (s *shardedSet[T]) add(elems ...T) {
+	for _, elem := range elems {
+		s.shardFor(elem).s.Add(elem)
+	}
+}
+
+func (s *shardedSet[T]) remove(elems ...T) {
+	for _, elem := range elems {
+		s.shardFor(elem).s.Remove(elem)
+	}
+}
+
+func (s *shardedSet[T]) contains(elems ...T) bool {
+	for _, elem := range elems {
+		if !s.shardFor(elem).s.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *shardedSet[T]) len() int {
+	n := 0
+	for i := range s.shards {
+		n += s.shards[i].s.Len()
+	}
+	return n
+}
+
+// flatten copies the elements of every shard into one unsafeSet.
+func (s *shardedSet[T]) flatten() *unsafeSet[T] {
+	unlock := s.rlockAll()
+	defer unlock()
+
+	ret := &unsafeSet[T]{m: make(map[T]struct{}, s.len())}
+	for i := range s.shards {
+		for elem := range s.shards[i].s.m {
+			ret.m[elem] = struct{}{}
+		}
+	}
+	return ret
+}
+
+func (s *shardedSet[T]) Add(i ...T) {
+	if err := s.tryAdd(i); err != nil {
+		panic(err)
+	}
+}
+
+// tryAdd adds i, or returns ErrFrozen if s is frozen.
+func (s *shardedSet[T]) tryAdd(i []T) error {
+	if len(i) == 1 {
+		sh := s.shardFor(i[0])
+		sh.Lock()
+		defer sh.Unlock()
+		if s.frozen.Load() {
+			return ErrFrozen
+		}
+		sh.s.Add(i[0])
+		return nil
+	}
+
+	unlock := s.lockAll()
+	defer unlock()
+	if s.frozen.Load() {
+		return ErrFrozen
+	}
+	s.add(i...)
+	return nil
+}
+
+func (s *shardedSet[T]) Contains(i ...T) bool {
+	if len(i) == 1 {
+		sh := s.shardFor(i[0])
+		sh.RLock()
+		defer sh.RUnlock()
+		return sh.s.Contains(i[0])
+	}
+
+	unlock := s.rlockAll()
+	defer unlock()
+	return s.contains(i...)
+}
+
+func (s *shardedSet[T]) TryAdd(i ...T) error {
+	if err := checkHashable(i); err != nil {
+		return err
+	}
+	return s.tryAdd(i)
+}
+
+func (s *shardedSet[T]) TryContains(i ...T) (bool, error) {
+	if err := checkHashable(i); err != nil {
+		return false, err
+	}
+	return s.Contains(i...), nil
+}
+
+func (s *shardedSet[T]) Clear() {
+	unlock := s.lockAll()
+	defer unlock()
+	checkFrozen(s.frozen.Load())
+	for i := range s.shards {
+		s.shards[i].s = newUnsafeSet[T]()
+	}
+}
+
+func (s *shardedSet[T]) Remove(i T) {
+	sh := s.shardFor(i)
+	sh.Lock()
+	defer sh.Unlock()
+	checkFrozen(s.frozen.Load())
+	sh.s.Remove(i)
+}
+
+func (s *shardedSet[T]) Len() int {
+	unlock := s.rlockAll()
+	defer unlock()
+	return s.len()
+}
+
+func (s *shardedSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *shardedSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
+}
+
+func (s *shardedSet[T]) Each(fn func(elem T) bool) {
+	unlock := s.rlockAll()
+	defer unlock()
+	for i := range s.shards {
+		for elem := range s.shards[i].s.m {
+			if !fn(elem) {
+				return
+			}
+		}
+	}
+}
+
+func (s *shardedSet[T]) Equal(other Collection[T]) bool {
+	elems := toSlice(other)
+	if checkHashable(elems) != nil {
+		return false
+	}
+
+	unlock := s.rlockAll()
+	defer unlock()
+	return len(elems) == s.len() && s.contains(elems...)
+}
+
+func (s *shardedSet[T]) Clone() TypedSet[T] {
+	unlock := s.rlockAll()
+	defer unlock()
+
+	ret := &shardedSet[T]{seed: s.seed, shards: make([]shard[T], len(s.shards))}
+	for i := range s.shards {
+		ret.shards[i].s = &unsafeSet[T]{m: maps.Clone(s.shards[i].s.m)}
+	}
+	return ret
+}
+
+func (s *shardedSet[T]) ToSlice() []T {
+	unlock := s.rlockAll()
+	defer unlock()
+
+	keys := make([]T, 0, s.len())
+	for i := range s.shards {
+		for elem := range s.shards[i].s.m {
+			keys = append(keys, elem)
+		}
+	}
+	return keys
+}
+
+func (s *shardedSet[T]) RemoveFrom(other Collection[T]) {
+	elems := hashable(toSlice(other))
+	unlock := s.lockAll()
+	defer unlock()
+	checkFrozen(s.frozen.Load())
+	s.remove(elems...)
+}
+
+func (s *shardedSet[T]) AddFrom(other Collection[T]) {
+	elems := toSlice(other)
+	unlock := s.lockAll()
+	defer unlock()
+	checkFrozen(s.frozen.Load())
+	s.add(elems...)
+}
+
+func (s *shardedSet[T]) RetainFrom(other Collection[T]) {
+	keep := newUnsafeSet[T]()
+	keep.Add(hashable(toSlice(other))...)
+	unlock := s.lockAll()
+	defer unlock()
+	checkFrozen(s.frozen.Load())
+	for i := range s.shards {
+		s.shards[i].s.RetainFrom(keep)
+	}
+}
+
+func (s *shardedSet[T]) Union(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).Union)
+}
+
+func (s *shardedSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).Intersect)
+}
+
+func (s *shardedSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).Difference)
+}
+
+func (s *shardedSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).SymmetricDifference)
+}
+
+// combine applies op to a flattened snapshot of s and to other, and spreads
+// the result over a new set with the shard layout of s.
+func (s *shardedSet[T]) combine(other Collection[T], op func(*unsafeSet[T], Collection[T]) TypedSet[T]) TypedSet[T] {
+	ret := s.empty()
+	for elem := range op(s.flatten(), other).(*unsafeSet[T]).m {
+		ret.add(elem)
+	}
+	return ret
+}
+
+func (s *shardedSet[T]) IsSubset(other Collection[T]) bool {
+	return isSubset[T](s, other)
+}
+
+func (s *shardedSet[T]) IsSuperset(other Collection[T]) bool {
+	return isSuperset[T](s, other)
+}
+
+func (s *shardedSet[T]) IsProperSubset(other Collection[T]) bool {
+	return isProperSubset[T](s, other)
+}
+
+func (s *shardedSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return isProperSuperset[T](s, other)
+}
+
+func (s *shardedSet[T]) IsDisjoint(other Collection[T]) bool {
+	return isDisjoint[T](s, other)
+}
@@ -0,0 +1,176 @@
+package set
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func init() {
+	constructors["ShardedSet"] = func(s ...interface{}) Set {
+		return NewShardedSet(4, s...)
+	}
+}
+
+func TestShardedSetSpreadsElements(t *testing.T) {
+	s := NewTypedShardedSet[int](8).(*shardedSet[int])
+	for i := 0; i < N; i++ {
+		s.Add(i)
+	}
+
+	for i := range s.shards {
+		if n := s.shards[i].s.Len(); n == 0 || n == N {
+			t.Errorf("shard %v holds %v of %v elements", i, n, N)
+		}
+	}
+
+	if s.Len() != N || len(s.ToSlice()) != N {
+		t.Errorf("expected %v elements, got %v", N, s.Len())
+	}
+}
+
+func TestShardedSetDefaultShards(t *testing.T) {
+	for _, shards := range []int{-1, 0} {
+		if got := len(NewShardedSet(shards).(*shardedSet[interface{}]).shards); got != DefaultShards {
+			t.Errorf("NewShardedSet(%v): expected %v shards, got %v", shards, DefaultShards, got)
+		}
+	}
+
+	if got := fmt.Sprintf("%#v", NewShardedSet(3, 1)); got != "set.NewShardedSetFromSlice(3, []interface {}{1})" {
+		t.Errorf("unexpected Go syntax %q", got)
+	}
+}
+
+type hashKey struct {
+	name string
+	n    float64
+	p    *int
+	v    interface{}
+	_    int
+	a    [2]uint8
+}
+
+func TestHashComparable(t *testing.T) {
+	seed := maphash.MakeSeed()
+	p := new(int)
+	for _, pair := range [][2]interface{}{
+		{1, 1},
+		{"a", "a"},
+		{0.0, math.Copysign(0, -1)},
+		{complex(0, 1), complex(math.Copysign(0, -1), 1)},
+		{hashKey{name: "a", n: 1, p: p, v: 2, a: [2]uint8{3, 4}}, hashKey{name: "a", n: 1, p: p, v: 2, a: [2]uint8{3, 4}}},
+		{hashKey{v: hashKey{n: 0}}, hashKey{v: hashKey{n: math.Copysign(0, -1)}}},
+		{[2]interface{}{nil, true}, [2]interface{}{nil, true}},
+	} {
+		if pair[0] != pair[1] {
+			t.Fatalf("%#v and %#v should be equal", pair[0], pair[1])
+		}
+		if a, b := hashComparable(seed, pair[0]), hashComparable(seed, pair[1]); a != b {
+			t.Errorf("equal values %#v and %#v hash to %x and %x", pair[0], pair[1], a, b)
+		}
+	}
+
+	seen := make(map[uint64]bool)
+	for _, v := range []interface{}{1, 2, "1", 1.5, hashKey{name: "a"}, hashKey{name: "b"}, hashKey{p: p}, [2]interface{}{1, nil}} {
+		seen[hashComparable(seed, v)] = true
+	}
+	if len(seen) < 8 {
+		t.Errorf("expected 8 different hashes, got %v", len(seen))
+	}
+
+	if !mustPanic(func() { hashComparable[interface{}](seed, []int{1}) }) {
+		t.Error("hashing a slice should panic")
+	}
+}
+
+func TestShardedSetAlgebraKeepsShards(t *testing.T) {
+	s := NewTypedShardedSet(5, 1, 2, 3)
+	for _, r := range []TypedSet[int]{
+		s.Clone(),
+		s.Union(NewTypedSet(4)),
+		s.Intersect(NewTypedSet(2, 3)),
+		s.SymmetricDifference(NewTypedSet(3, 4)),
+	} {
+		if got := len(r.(*shardedSet[int]).shards); got != 5 {
+			t.Errorf("expected 5 shards, got %v", got)
+		}
+
+		r.Add(10)
+		if !r.Contains(10) || s.Contains(10) {
+			t.Errorf("expected an independent set, got %v", r)
+		}
+	}
+}
+
+func TestShardedSetCloneKeepsNil(t *testing.T) {
+	s := NewShardedSet(2, nil, 1)
+	if c := s.Clone(); c.Len() != 2 || !c.Contains(nil, 1) {
+		t.Errorf("expected {<nil>, 1}, got %v", c)
+	}
+}
+
+func TestShardedSetConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(4)
+
+	s := NewTypedShardedSet[int](8)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < N; i += 8 {
+				s.Add(i)
+				s.Add(i+N, i+2*N)
+				s.Remove(i + N)
+				if !s.Contains(i, i+2*N) {
+					t.Errorf("missing %v", i)
+				}
+				s.Len()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if s.Len() != 2*N {
+		t.Errorf("expected %v elements, got %v", 2*N, s.Len())
+	}
+}
+
+// benchmarkContended runs a read-mostly workload from about 64 goroutines
+// sharing one set.
+func benchmarkContended(b *testing.B, s Set) {
+	for i := 0; i < N; i++ {
+		s.Add(i)
+	}
+
+	procs := runtime.GOMAXPROCS(0)
+	b.SetParallelism((64 + procs - 1) / procs)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			v := r.Intn(2 * N)
+			if v%10 == 0 {
+				s.Add(v)
+			} else {
+				s.Contains(v)
+			}
+		}
+	})
+}
+
+func BenchmarkContendedSet(b *testing.B) {
+	benchmarkContended(b, NewSet())
+}
+
+func BenchmarkContendedOrderedSet(b *testing.B) {
+	benchmarkContended(b, NewOrderedSet())
+}
+
+func BenchmarkContendedShardedSet(b *testing.B) {
+	benchmarkContended(b, NewShardedSet(0))
+}
@@ -0,0 +1,290 @@
+package set
+
+import "sync"
+
+var (
+	sts *safeSortedSet[interface{}]
+	_   SortedSet = sts
+)
+
+type safeSortedSet[T any] struct {
+	s *unsafeSortedSet[T]
+	sync.RWMutex
+	lockOrder
+}
+
+func newSafeSortedSet[T any](compare func(a, b T) int) safeSortedSet[T] {
+	return safeSortedSet[T]{s: newUnsafeSortedSet(compare)}
+}
+
+func (s *safeSortedSet[T]) Add(i ...T) {
+	s.Lock()
+	defer s.Unlock()
+	s.s.Add(i...)
+}
+
+func (s *safeSortedSet[T]) Contains(i ...T) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Contains(i...)
+}
+
+func (s *safeSortedSet[T]) Clear() {
+	s.Lock()
+	defer s.Unlock()
+	s.s.Clear()
+}
+
+func (s *safeSortedSet[T]) Remove(i T) {
+	s.Lock()
+	defer s.Unlock()
+	s.s.Remove(i)
+}
+
+func (s *safeSortedSet[T]) Len() int {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Len()
+}
+
+func (s *safeSortedSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *safeSortedSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
+}
+
+func (s *safeSortedSet[T]) Each(fn func(elem T) bool) {
+	s.RLock()
+	defer s.RUnlock()
+	s.s.Each(fn)
+}
+
+func (s *safeSortedSet[T]) Equal(other Collection[T]) bool {
+	o, ok := other.(*safeSortedSet[T])
+	if !ok {
+		elems := toSlice(other)
+		s.RLock()
+		defer s.RUnlock()
+		return len(elems) == s.s.Len() && s.s.Contains(elems...)
+	}
+
+	unlock := lockPair(s, o, false)
+	defer unlock()
+	return s.s.Equal(o.s)
+}
+
+func (s *safeSortedSet[T]) Clone() TypedSet[T] {
+	s.RLock()
+	defer s.RUnlock()
+	return &safeSortedSet[T]{s: s.s.Clone().(*unsafeSortedSet[T])}
+}
+
+func (s *safeSortedSet[T]) ToSlice() []T {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.ToSlice()
+}
+
+func (s *safeSortedSet[T]) RemoveFrom(other Collection[T]) {
+	o, ok := other.(*safeSortedSet[T])
+	if !ok {
+		elems := toSlice(other)
+		s.Lock()
+		defer s.Unlock()
+		checkFrozen(s.s.frozen)
+		for _, elem := range elems {
+			s.s.Remove(elem)
+		}
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.RemoveFrom(o.s)
+}
+
+func (s *safeSortedSet[T]) AddFrom(other Collection[T]) {
+	o, ok := other.(*safeSortedSet[T])
+	if !ok {
+		elems := toSlice(other)
+		s.Lock()
+		defer s.Unlock()
+		s.s.Add(elems...)
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.AddFrom(o.s)
+}
+
+func (s *safeSortedSet[T]) RetainFrom(other Collection[T]) {
+	o, ok := other.(*safeSortedSet[T])
+	if !ok {
+		keep := s.snapshot(other)
+		s.Lock()
+		defer s.Unlock()
+		s.s.RetainFrom(keep)
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.RetainFrom(o.s)
+}
+
+func (s *safeSortedSet[T]) Union(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSortedSet[T]).Union)
+}
+
+func (s *safeSortedSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSortedSet[T]).Intersect)
+}
+
+func (s *safeSortedSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSortedSet[T]).Difference)
+}
+
+func (s *safeSortedSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSortedSet[T]).SymmetricDifference)
+}
+
+// combine applies op to the unsafe contents of s and other, holding both
+// read locks when other is a safeSortedSet and working on a snapshot of
+// other otherwise.
+func (s *safeSortedSet[T]) combine(other Collection[T], op func(*unsafeSortedSet[T], Collection[T]) TypedSet[T]) TypedSet[T] {
+	if o, ok := other.(*safeSortedSet[T]); ok {
+		unlock := lockPair(s, o, false)
+		defer unlock()
+		return &safeSortedSet[T]{s: op(s.s, o.s).(*unsafeSortedSet[T])}
+	}
+
+	snapshot := s.snapshot(other)
+	s.RLock()
+	defer s.RUnlock()
+	return &safeSortedSet[T]{s: op(s.s, snapshot).(*unsafeSortedSet[T])}
+}
+
+func (s *safeSortedSet[T]) IsSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSortedSet[T]).IsSubset, isSubset[T])
+}
+
+func (s *safeSortedSet[T]) IsSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSortedSet[T]).IsSuperset, isSuperset[T])
+}
+
+func (s *safeSortedSet[T]) IsProperSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSortedSet[T]).IsProperSubset, isProperSubset[T])
+}
+
+func (s *safeSortedSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSortedSet[T]).IsProperSuperset, isProperSuperset[T])
+}
+
+func (s *safeSortedSet[T]) IsDisjoint(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSortedSet[T]).IsDisjoint, isDisjoint[T])
+}
+
+// relate applies op to the unsafe contents of s and other, holding both
+// read locks, when other is a safeSortedSet, and otherwise answers with
+// fallback, which reads the two sets one at a time.
+func (s *safeSortedSet[T]) relate(other Collection[T], op func(*unsafeSortedSet[T], Collection[T]) bool, fallback func(s, other Collection[T]) bool) bool {
+	if o, ok := other.(*safeSortedSet[T]); ok {
+		unlock := lockPair(s, o, false)
+		defer unlock()
+		return op(s.s, o.s)
+	}
+
+	return fallback(s, other)
+}
+
+// snapshot copies other into an unsafeSortedSet using the comparator of s,
+// so that other is read before the lock on s is taken and its elements are
+// never hashed.
+func (s *safeSortedSet[T]) snapshot(other Collection[T]) *unsafeSortedSet[T] {
+	s.RLock()
+	ret := newUnsafeSortedSet(s.s.compare)
+	s.RUnlock()
+	ret.Add(toSlice(other)...)
+	return ret
+}
+
+func (s *safeSortedSet[T]) Min() (T, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Min()
+}
+
+func (s *safeSortedSet[T]) Max() (T, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Max()
+}
+
+func (s *safeSortedSet[T]) Floor(elem T) (T, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Floor(elem)
+}
+
+func (s *safeSortedSet[T]) Ceiling(elem T) (T, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Ceiling(elem)
+}
+
+func (s *safeSortedSet[T]) Lower(elem T) (T, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Lower(elem)
+}
+
+func (s *safeSortedSet[T]) Higher(elem T) (T, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Higher(elem)
+}
+
+func (s *safeSortedSet[T]) SubSet(lo, hi T, inclusiveLo, inclusiveHi bool) TypedSortedSet[T] {
+	s.RLock()
+	defer s.RUnlock()
+	return &safeSortedSet[T]{s: s.s.SubSet(lo, hi, inclusiveLo, inclusiveHi).(*unsafeSortedSet[T])}
+}
+
+func (s *safeSortedSet[T]) HeadSet(hi T, inclusive bool) TypedSortedSet[T] {
+	s.RLock()
+	defer s.RUnlock()
+	return &safeSortedSet[T]{s: s.s.HeadSet(hi, inclusive).(*unsafeSortedSet[T])}
+}
+
+func (s *safeSortedSet[T]) TailSet(lo T, inclusive bool) TypedSortedSet[T] {
+	s.RLock()
+	defer s.RUnlock()
+	return &safeSortedSet[T]{s: s.s.TailSet(lo, inclusive).(*unsafeSortedSet[T])}
+}
+
+func (s *safeSortedSet[T]) RangeIterator(lo, hi T, inclusiveLo, inclusiveHi bool) *Iterator[T] {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.RangeIterator(lo, hi, inclusiveLo, inclusiveHi)
+}
+
+func (s *safeSortedSet[T]) Rank(elem T) int {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Rank(elem)
+}
+
+func (s *safeSortedSet[T]) Select(k int) (T, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Select(k)
+}
+
+func (s *safeSortedSet[T]) CountRange(lo, hi T) int {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.CountRange(lo, hi)
+}
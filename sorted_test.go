@@ -0,0 +1,439 @@
+package set
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// compareAny orders values of different types by type name, and values of
+// the same type by value.
+func compareAny(a, b interface{}) int {
+	if ta, tb := fmt.Sprintf("%T", a), fmt.Sprintf("%T", b); ta != tb {
+		return cmp.Compare(ta, tb)
+	}
+
+	switch a := a.(type) {
+	case int:
+		return cmp.Compare(a, b.(int))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case string:
+		return cmp.Compare(a, b.(string))
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func init() {
+	constructors["SortedSet"] = func(s ...interface{}) Set {
+		return NewSortedSet(compareAny, s...)
+	}
+	constructors["UnsafeSortedSet"] = func(s ...interface{}) Set {
+		return NewUnsafeSortedSetFromSlice(compareAny, s)
+	}
+}
+
+// checkTree verifies the left-leaning red-black invariants and returns the
+// black height of h.
+func checkTree[T comparable](t *testing.T, s *unsafeSortedSet[T], h *sortedNode[T]) int {
+	t.Helper()
+	if h == nil {
+		return 1
+	}
+
+	if isRed(h.right) {
+		t.Fatalf("right-leaning red link at %v", h.value)
+	}
+	if isRed(h) && isRed(h.left) {
+		t.Fatalf("two red links in a row at %v", h.value)
+	}
+	if h.left != nil && s.compare(h.left.value, h.value) >= 0 {
+		t.Fatalf("left child %v is not less than %v", h.left.value, h.value)
+	}
+	if h.right != nil && s.compare(h.right.value, h.value) <= 0 {
+		t.Fatalf("right child %v is not greater than %v", h.right.value, h.value)
+	}
+
+	if h.size != size(h.left)+size(h.right)+1 {
+		t.Fatalf("wrong subtree size at %v: %v", h.value, h.size)
+	}
+
+	l, r := checkTree(t, s, h.left), checkTree(t, s, h.right)
+	if l != r {
+		t.Fatalf("unbalanced black height at %v: %v != %v", h.value, l, r)
+	}
+
+	if !isRed(h) {
+		l++
+	}
+	return l
+}
+
+func TestSortedSetRandom(t *testing.T) {
+	s := newUnsafeSortedSet(cmp.Compare[int])
+	model := make(map[int]bool)
+
+	for i := 0; i < 20*N; i++ {
+		v := rand.Intn(N)
+		if rand.Intn(3) == 0 {
+			s.Remove(v)
+			delete(model, v)
+		} else {
+			s.Add(v)
+			model[v] = true
+		}
+
+		if s.Len() != len(model) {
+			t.Fatalf("expected Len %v, got %v", len(model), s.Len())
+		}
+	}
+
+	if s.root != nil && s.root.red {
+		t.Fatal("the root should be black")
+	}
+	checkTree(t, s, s.root)
+
+	prev := -1
+	for _, v := range s.ToSlice() {
+		if v <= prev || !model[v] {
+			t.Fatalf("unexpected element %v after %v", v, prev)
+		}
+		prev = v
+	}
+}
+
+func TestSortedSetOrder(t *testing.T) {
+	for _, s := range []TypedSortedSet[int]{NewTypedSortedSet(5, 3, 9, 1, 3), NewTypedUnsafeSortedSet[int]()} {
+		s.Add(5, 3, 9, 1)
+		if got := s.ToSlice(); fmt.Sprint(got) != "[1 3 5 9]" {
+			t.Errorf("expected [1 3 5 9], got %v", got)
+		}
+
+		s.Remove(3)
+		s.Remove(4)
+		if got := s.ToSlice(); fmt.Sprint(got) != "[1 5 9]" {
+			t.Errorf("expected [1 5 9], got %v", got)
+		}
+	}
+}
+
+func TestSortedSetNavigation(t *testing.T) {
+	for _, s := range []TypedSortedSet[int]{NewTypedSortedSet(10, 20, 30), NewTypedUnsafeSortedSetFromSlice([]int{10, 20, 30})} {
+		for _, tt := range []struct {
+			name string
+			fn   func(int) (int, bool)
+			in   int
+			want int
+			ok   bool
+		}{
+			{"Floor", s.Floor, 20, 20, true},
+			{"Floor", s.Floor, 25, 20, true},
+			{"Floor", s.Floor, 5, 0, false},
+			{"Ceiling", s.Ceiling, 20, 20, true},
+			{"Ceiling", s.Ceiling, 15, 20, true},
+			{"Ceiling", s.Ceiling, 35, 0, false},
+			{"Lower", s.Lower, 20, 10, true},
+			{"Lower", s.Lower, 10, 0, false},
+			{"Lower", s.Lower, 99, 30, true},
+			{"Higher", s.Higher, 20, 30, true},
+			{"Higher", s.Higher, 30, 0, false},
+			{"Higher", s.Higher, -1, 10, true},
+		} {
+			if got, ok := tt.fn(tt.in); got != tt.want || ok != tt.ok {
+				t.Errorf("%v(%v): expected %v, %v, got %v, %v", tt.name, tt.in, tt.want, tt.ok, got, ok)
+			}
+		}
+
+		if min, ok := s.Min(); !ok || min != 10 {
+			t.Errorf("Min: expected 10, got %v", min)
+		}
+
+		if max, ok := s.Max(); !ok || max != 30 {
+			t.Errorf("Max: expected 30, got %v", max)
+		}
+
+		s.Clear()
+		if _, ok := s.Min(); ok {
+			t.Error("Min of an empty set should report that there is no element")
+		}
+		if _, ok := s.Max(); ok {
+			t.Error("Max of an empty set should report that there is no element")
+		}
+	}
+}
+
+func TestSortedSetComparatorEquality(t *testing.T) {
+	byLen := func(a, b string) int { return cmp.Compare(len(a), len(b)) }
+	s := NewTypedSortedSetFunc(byLen, "aa", "b", "cc")
+
+	if s.Len() != 2 {
+		t.Errorf("elements the comparator finds equal should be deduped, got %v", s.ToSlice())
+	}
+
+	if !s.Contains("zz") {
+		t.Error("Contains should use the comparator")
+	}
+}
+
+func TestSortedSetOfSlicesAgainstEveryKind(t *testing.T) {
+	for _, b := range []func(...interface{}) Set{constructors["SortedSet"], constructors["UnsafeSortedSet"]} {
+		for name, a := range constructors {
+			s := func() Set { return b(1, []int{1}) }
+
+			if i := s().Intersect(a(1, 2)); !i.Equal(b(1)) {
+				t.Errorf("%v: expected {1}, got %v", name, i)
+			}
+			if d := s().Difference(a(1, 2)); !d.Equal(b([]int{1})) {
+				t.Errorf("%v: expected {[1]}, got %v", name, d)
+			}
+			if s().Equal(a(1, 2)) || s().IsSubset(a(1, 2)) || !s().IsDisjoint(a(2)) {
+				t.Errorf("%v: wrong predicates of a sorted set holding a slice", name)
+			}
+
+			r := s()
+			r.RemoveFrom(a(1))
+			if !r.Equal(b([]int{1})) {
+				t.Errorf("%v: expected {[1]}, got %v", name, r)
+			}
+			r = s()
+			r.RetainFrom(a(1))
+			if !r.Equal(b(1)) {
+				t.Errorf("%v: expected {1}, got %v", name, r)
+			}
+		}
+	}
+}
+
+func TestSortedSetCrossKindUnhashable(t *testing.T) {
+	compareSlices := func(a, b interface{}) int { return slices.Compare(a.([]int), b.([]int)) }
+	s := NewSortedSet(compareSlices, []int{1}, []int{2})
+	other := NewUnsafeSortedSetFromSlice(compareSlices, []interface{}{[]int{2}, []int{3}})
+
+	if u := s.Union(other); u.Len() != 3 {
+		t.Errorf("expected a union of 3 elements, got %v", u)
+	}
+	if i := s.Intersect(other); i.Len() != 1 || !i.Contains([]int{2}) {
+		t.Errorf("expected an intersection of [2], got %v", i)
+	}
+	if s.IsSubset(other) || !s.IsSuperset(NewUnsafeSortedSetFromSlice(compareSlices, []interface{}{[]int{1}})) {
+		t.Error("wrong predicates against an unsafe sorted set")
+	}
+
+	s.RetainFrom(other)
+	if s.Len() != 1 || !s.Contains([]int{2}) {
+		t.Errorf("expected [2] to be retained, got %v", s)
+	}
+}
+
+func TestSortedSetCrossKindComparator(t *testing.T) {
+	fold := func(a, b string) int { return cmp.Compare(strings.ToLower(a), strings.ToLower(b)) }
+	other := NewTypedUnsafeSortedSetFuncFromSlice(fold, []string{"A", "C"})
+	safe := func() TypedSortedSet[string] { return NewTypedSortedSetFunc(fold, "a", "b") }
+	unsafe := func() TypedSortedSet[string] { return NewTypedUnsafeSortedSetFuncFromSlice(fold, []string{"a", "b"}) }
+
+	for name, op := range map[string]func(s TypedSortedSet[string]) TypedSet[string]{
+		"Union":               func(s TypedSortedSet[string]) TypedSet[string] { return s.Union(other) },
+		"Intersect":           func(s TypedSortedSet[string]) TypedSet[string] { return s.Intersect(other) },
+		"Difference":          func(s TypedSortedSet[string]) TypedSet[string] { return s.Difference(other) },
+		"SymmetricDifference": func(s TypedSortedSet[string]) TypedSet[string] { return s.SymmetricDifference(other) },
+		"RetainFrom":          func(s TypedSortedSet[string]) TypedSet[string] { s.RetainFrom(other); return s },
+	} {
+		if got, want := op(safe()).ToSlice(), op(unsafe()).ToSlice(); !slices.Equal(got, want) {
+			t.Errorf("%v: expected %v like the unsafe sorted set, got %v", name, want, got)
+		}
+	}
+
+	if !NewTypedSortedSetFunc(fold, "a").IsSubset(other) || safe().IsDisjoint(other) {
+		t.Error("predicates should use the comparator")
+	}
+}
+
+func TestSortedSetString(t *testing.T) {
+	s := NewSortedSet(compareAny, 3, 1, 2)
+	if got := fmt.Sprint(s); got != "SortedSet{1, 2, 3}" {
+		t.Errorf("expected SortedSet{1, 2, 3}, got %v", got)
+	}
+
+	if got := fmt.Sprintf("%#v", NewTypedUnsafeSortedSet[int]()); got != "set.NewTypedUnsafeSortedSetFuncFromSlice(compare, []int{})" {
+		t.Errorf("unexpected Go syntax %v", got)
+	}
+	if got := fmt.Sprintf("%#v", NewTypedSortedSetFromSlice([]int{2, 1})); got != "set.NewTypedSortedSetFuncFromSlice(compare, []int{1, 2})" {
+		t.Errorf("unexpected Go syntax %v", got)
+	}
+	if got := fmt.Sprintf("%#v", NewSortedSetFromSlice(compareAny, []interface{}{1})); got != "set.NewSortedSetFromSlice(compare, []interface {}{1})" {
+		t.Errorf("unexpected Go syntax %v", got)
+	}
+}
+
+func TestTypedSortedSetOfSlices(t *testing.T) {
+	for _, s := range []TypedSortedSet[[]int]{
+		NewTypedSortedSetFunc(slices.Compare[[]int], []int{2}, []int{1, 5}),
+		NewTypedUnsafeSortedSetFuncFromSlice(slices.Compare[[]int], [][]int{{2}, {1, 5}}),
+	} {
+		s.Add([]int{1, 5})
+		if first, _ := s.Min(); s.Len() != 2 || !slices.Equal(first, []int{1, 5}) || !s.Contains([]int{2}) {
+			t.Errorf("expected {[1 5], [2]}, got %v", s)
+		}
+	}
+}
+
+func TestSortedSetRanges(t *testing.T) {
+	for _, s := range []TypedSortedSet[int]{NewTypedSortedSet(1, 2, 3, 4, 5, 6), NewTypedUnsafeSortedSetFromSlice([]int{6, 5, 4, 3, 2, 1})} {
+		for _, tt := range []struct {
+			name string
+			got  TypedSortedSet[int]
+			want string
+		}{
+			{"SubSet[2,5)", s.SubSet(2, 5, true, false), "[2 3 4]"},
+			{"SubSet(2,5]", s.SubSet(2, 5, false, true), "[3 4 5]"},
+			{"SubSet[2,5]", s.SubSet(2, 5, true, true), "[2 3 4 5]"},
+			{"SubSet(0,9)", s.SubSet(0, 9, false, false), "[1 2 3 4 5 6]"},
+			{"SubSet(5,2)", s.SubSet(5, 2, true, true), "[]"},
+			{"HeadSet<4", s.HeadSet(4, false), "[1 2 3]"},
+			{"HeadSet<=4", s.HeadSet(4, true), "[1 2 3 4]"},
+			{"TailSet>4", s.TailSet(4, false), "[5 6]"},
+			{"TailSet>=4", s.TailSet(4, true), "[4 5 6]"},
+		} {
+			if got := fmt.Sprint(tt.got.ToSlice()); got != tt.want {
+				t.Errorf("%v: expected %v, got %v", tt.name, tt.want, got)
+			}
+
+			if fmt.Sprintf("%T", tt.got) != fmt.Sprintf("%T", s) {
+				t.Errorf("%v: expected a %T, got a %T", tt.name, s, tt.got)
+			}
+		}
+
+		head := s.HeadSet(3, true)
+		head.Add(0)
+		s.Remove(1)
+		if !head.Contains(0, 1) || s.Contains(0) {
+			t.Error("range results should be independent copies")
+		}
+
+		var got []int
+		for it := s.RangeIterator(2, 5, false, true); it.Next(); {
+			got = append(got, it.Value())
+		}
+		if fmt.Sprint(got) != "[3 4 5]" {
+			t.Errorf("RangeIterator(2,5]: expected [3 4 5], got %v", got)
+		}
+	}
+}
+
+func TestSortedSetRangesRandom(t *testing.T) {
+	s := newUnsafeSortedSet(cmp.Compare[int])
+	for _, v := range rand.Perm(N) {
+		s.Add(v * 2)
+	}
+
+	for i := 0; i < 100; i++ {
+		lo, hi := rand.Intn(2*N), rand.Intn(2*N)
+		incLo, incHi := rand.Intn(2) == 0, rand.Intn(2) == 0
+
+		var want []int
+		for v := 0; v < 2*N; v += 2 {
+			if (v > lo || incLo && v == lo) && (v < hi || incHi && v == hi) {
+				want = append(want, v)
+			}
+		}
+
+		sub := s.SubSet(lo, hi, incLo, incHi).(*unsafeSortedSet[int])
+		if got := sub.ToSlice(); fmt.Sprint(got) != fmt.Sprint(want) && len(got)+len(want) > 0 {
+			t.Fatalf("SubSet(%v, %v, %v, %v): expected %v, got %v", lo, hi, incLo, incHi, want, got)
+		}
+		checkTree(t, sub, sub.root)
+	}
+}
+
+func TestSortedSetRankSelect(t *testing.T) {
+	for _, s := range []TypedSortedSet[int]{NewTypedSortedSet(10, 20, 30, 40), NewTypedUnsafeSortedSetFromSlice([]int{40, 30, 20, 10})} {
+		for _, tt := range []struct{ elem, rank int }{{5, 0}, {10, 0}, {15, 1}, {20, 1}, {40, 3}, {45, 4}} {
+			if got := s.Rank(tt.elem); got != tt.rank {
+				t.Errorf("Rank(%v): expected %v, got %v", tt.elem, tt.rank, got)
+			}
+		}
+
+		for k, want := range []int{10, 20, 30, 40} {
+			if got, ok := s.Select(k); !ok || got != want {
+				t.Errorf("Select(%v): expected %v, got %v", k, want, got)
+			}
+		}
+
+		if _, ok := s.Select(-1); ok {
+			t.Error("Select(-1) should report that there is no such element")
+		}
+		if _, ok := s.Select(4); ok {
+			t.Error("Select(4) should report that there is no such element")
+		}
+
+		for _, tt := range []struct{ lo, hi, count int }{{10, 40, 3}, {10, 41, 4}, {0, 100, 4}, {15, 35, 2}, {30, 30, 0}, {40, 10, 0}} {
+			if got := s.CountRange(tt.lo, tt.hi); got != tt.count {
+				t.Errorf("CountRange(%v, %v): expected %v, got %v", tt.lo, tt.hi, tt.count, got)
+			}
+		}
+	}
+}
+
+func TestSortedSetRankSelectRandom(t *testing.T) {
+	s := newUnsafeSortedSet(cmp.Compare[int])
+	for i := 0; i < 10*N; i++ {
+		if v := rand.Intn(N); rand.Intn(3) == 0 {
+			s.Remove(v)
+		} else {
+			s.Add(v)
+		}
+	}
+	checkTree(t, s, s.root)
+
+	for k, v := range s.ToSlice() {
+		if got := s.Rank(v); got != k {
+			t.Fatalf("Rank(%v): expected %v, got %v", v, k, got)
+		}
+
+		if got, _ := s.Select(k); got != v {
+			t.Fatalf("Select(%v): expected %v, got %v", k, v, got)
+		}
+	}
+}
+
+func benchmarkSortedSet(n int) TypedSortedSet[int] {
+	s := NewTypedSortedSet[int]()
+	for _, v := range rand.Perm(n) {
+		s.Add(v)
+	}
+	return s
+}
+
+func BenchmarkSortedSetRank(b *testing.B) {
+	s := benchmarkSortedSet(100 * N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Rank(i % (100 * N))
+	}
+}
+
+func BenchmarkSortedSetSelect(b *testing.B) {
+	s := benchmarkSortedSet(100 * N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Select(i % (100 * N))
+	}
+}
+
+func BenchmarkSortedSetCountRange(b *testing.B) {
+	s := benchmarkSortedSet(100 * N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lo := i % (100 * N)
+		s.CountRange(lo, lo+N)
+	}
+}
+
+func BenchmarkSortedSetAdd(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchmarkSortedSet(N)
+	}
+}
@@ -0,0 +1,554 @@
+package set
+
+var (
+	uss *unsafeSortedSet[interface{}]
+	_   SortedSet = uss
+)
+
+// unsafeSortedSet keeps its elements in a left-leaning red-black tree
+// ordered by compare. Two elements are the same member when compare returns
+// 0 for them. Every node records the size of its subtree, which makes rank
+// and select queries O(log n).
+type unsafeSortedSet[T any] struct {
+	root    *sortedNode[T]
+	compare func(a, b T) int
+	frozen  bool
+}
+
+type sortedNode[T any] struct {
+	value T
+	left  *sortedNode[T]
+	right *sortedNode[T]
+	red   bool
+	size  int
+}
+
+func newUnsafeSortedSet[T any](compare func(a, b T) int) *unsafeSortedSet[T] {
+	return &unsafeSortedSet[T]{compare: compare}
+}
+
+func isRed[T any](h *sortedNode[T]) bool {
+	return h != nil && h.red
+}
+
+func size[T any](h *sortedNode[T]) int {
+	if h == nil {
+		return 0
+	}
+	return h.size
+}
+
+func rotateLeft[T any](h *sortedNode[T]) *sortedNode[T] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.red = h.red
+	h.red = true
+	x.size = h.size
+	h.size = size(h.left) + size(h.right) + 1
+	return x
+}
+
+func rotateRight[T any](h *sortedNode[T]) *sortedNode[T] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.red = h.red
+	h.red = true
+	x.size = h.size
+	h.size = size(h.left) + size(h.right) + 1
+	return x
+}
+
+func flipColors[T any](h *sortedNode[T]) {
+	h.red = !h.red
+	h.left.red = !h.left.red
+	h.right.red = !h.right.red
+}
+
+func balance[T any](h *sortedNode[T]) *sortedNode[T] {
+	if isRed(h.right) && !isRed(h.left) {
+		h = rotateLeft(h)
+	}
+	if isRed(h.left) && isRed(h.left.left) {
+		h = rotateRight(h)
+	}
+	if isRed(h.left) && isRed(h.right) {
+		flipColors(h)
+	}
+	h.size = size(h.left) + size(h.right) + 1
+	return h
+}
+
+func moveRedLeft[T any](h *sortedNode[T]) *sortedNode[T] {
+	flipColors(h)
+	if isRed(h.right.left) {
+		h.right = rotateRight(h.right)
+		h = rotateLeft(h)
+		flipColors(h)
+	}
+	return h
+}
+
+func moveRedRight[T any](h *sortedNode[T]) *sortedNode[T] {
+	flipColors(h)
+	if isRed(h.left.left) {
+		h = rotateRight(h)
+		flipColors(h)
+	}
+	return h
+}
+
+func (s *unsafeSortedSet[T]) insert(h *sortedNode[T], v T) *sortedNode[T] {
+	if h == nil {
+		return &sortedNode[T]{value: v, red: true, size: 1}
+	}
+
+	switch c := s.compare(v, h.value); {
+	case c < 0:
+		h.left = s.insert(h.left, v)
+	case c > 0:
+		h.right = s.insert(h.right, v)
+	default:
+		return h
+	}
+	return balance(h)
+}
+
+// delete removes v from the tree rooted at h, which must contain it.
+func (s *unsafeSortedSet[T]) delete(h *sortedNode[T], v T) *sortedNode[T] {
+	if s.compare(v, h.value) < 0 {
+		if !isRed(h.left) && !isRed(h.left.left) {
+			h = moveRedLeft(h)
+		}
+		h.left = s.delete(h.left, v)
+	} else {
+		if isRed(h.left) {
+			h = rotateRight(h)
+		}
+		if s.compare(v, h.value) == 0 && h.right == nil {
+			return nil
+		}
+		if !isRed(h.right) && !isRed(h.right.left) {
+			h = moveRedRight(h)
+		}
+		if s.compare(v, h.value) == 0 {
+			h.value = minNode(h.right).value
+			h.right = deleteMin(h.right)
+		} else {
+			h.right = s.delete(h.right, v)
+		}
+	}
+	return balance(h)
+}
+
+func deleteMin[T any](h *sortedNode[T]) *sortedNode[T] {
+	if h.left == nil {
+		return nil
+	}
+	if !isRed(h.left) && !isRed(h.left.left) {
+		h = moveRedLeft(h)
+	}
+	h.left = deleteMin(h.left)
+	return balance(h)
+}
+
+func minNode[T any](h *sortedNode[T]) *sortedNode[T] {
+	for h.left != nil {
+		h = h.left
+	}
+	return h
+}
+
+func maxNode[T any](h *sortedNode[T]) *sortedNode[T] {
+	for h.right != nil {
+		h = h.right
+	}
+	return h
+}
+
+func cloneNode[T any](h *sortedNode[T]) *sortedNode[T] {
+	if h == nil {
+		return nil
+	}
+	return &sortedNode[T]{value: h.value, left: cloneNode(h.left), right: cloneNode(h.right), red: h.red, size: h.size}
+}
+
+// walk calls fn on the values under h in order, and reports false if fn
+// stopped the walk.
+func walk[T any](h *sortedNode[T], fn func(elem T) bool) bool {
+	if h == nil {
+		return true
+	}
+	return walk(h.left, fn) && fn(h.value) && walk(h.right, fn)
+}
+
+func (s *unsafeSortedSet[T]) find(v T) *sortedNode[T] {
+	h := s.root
+	for h != nil {
+		switch c := s.compare(v, h.value); {
+		case c < 0:
+			h = h.left
+		case c > 0:
+			h = h.right
+		default:
+			return h
+		}
+	}
+	return nil
+}
+
+func (s *unsafeSortedSet[T]) Add(i ...T) {
+	checkFrozen(s.frozen)
+	for _, item := range i {
+		s.root = s.insert(s.root, item)
+		s.root.red = false
+	}
+}
+
+func (s *unsafeSortedSet[T]) Contains(i ...T) bool {
+	for _, item := range i {
+		if s.find(item) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeSortedSet[T]) Clear() {
+	checkFrozen(s.frozen)
+	s.root = nil
+}
+
+func (s *unsafeSortedSet[T]) Remove(i T) {
+	checkFrozen(s.frozen)
+	if s.find(i) == nil {
+		return
+	}
+
+	if !isRed(s.root.left) && !isRed(s.root.right) {
+		s.root.red = true
+	}
+	s.root = s.delete(s.root, i)
+	if s.root != nil {
+		s.root.red = false
+	}
+}
+
+func (s *unsafeSortedSet[T]) Len() int {
+	return size(s.root)
+}
+
+func (s *unsafeSortedSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *unsafeSortedSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
+}
+
+func (s *unsafeSortedSet[T]) Each(fn func(elem T) bool) {
+	walk(s.root, fn)
+}
+
+func (s *unsafeSortedSet[T]) Equal(other Collection[T]) bool {
+	if s.Len() != other.Len() {
+		return false
+	}
+	return s.IsSubset(other)
+}
+
+func (s *unsafeSortedSet[T]) Clone() TypedSet[T] {
+	return &unsafeSortedSet[T]{root: cloneNode(s.root), compare: s.compare}
+}
+
+func (s *unsafeSortedSet[T]) ToSlice() []T {
+	keys := make([]T, 0, s.Len())
+	s.Each(func(elem T) bool {
+		keys = append(keys, elem)
+		return true
+	})
+	return keys
+}
+
+func (s *unsafeSortedSet[T]) RemoveFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	for _, elem := range s.ToSlice() {
+		if contains(other, elem) {
+			s.Remove(elem)
+		}
+	}
+}
+
+func (s *unsafeSortedSet[T]) AddFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	s.Add(toSlice(other)...)
+}
+
+func (s *unsafeSortedSet[T]) RetainFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	for _, elem := range s.ToSlice() {
+		if !contains(other, elem) {
+			s.Remove(elem)
+		}
+	}
+}
+
+func (s *unsafeSortedSet[T]) Union(other Collection[T]) TypedSet[T] {
+	ret := s.Clone()
+	ret.AddFrom(other)
+	return ret
+}
+
+func (s *unsafeSortedSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	ret := newUnsafeSortedSet(s.compare)
+	s.Each(func(elem T) bool {
+		if contains(other, elem) {
+			ret.Add(elem)
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *unsafeSortedSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	ret := newUnsafeSortedSet(s.compare)
+	s.Each(func(elem T) bool {
+		if !contains(other, elem) {
+			ret.Add(elem)
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *unsafeSortedSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	ret := s.Difference(other).(*unsafeSortedSet[T])
+	for _, elem := range toSlice(other) {
+		if !s.Contains(elem) {
+			ret.Add(elem)
+		}
+	}
+	return ret
+}
+
+func (s *unsafeSortedSet[T]) IsSubset(other Collection[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+
+	ret := true
+	s.Each(func(elem T) bool {
+		ret = contains(other, elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *unsafeSortedSet[T]) IsSuperset(other Collection[T]) bool {
+	if s.Len() < other.Len() {
+		return false
+	}
+
+	ret := true
+	other.Each(func(elem T) bool {
+		ret = s.find(elem) != nil
+		return ret
+	})
+	return ret
+}
+
+func (s *unsafeSortedSet[T]) IsProperSubset(other Collection[T]) bool {
+	return s.Len() < other.Len() && s.IsSubset(other)
+}
+
+func (s *unsafeSortedSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return s.Len() > other.Len() && s.IsSuperset(other)
+}
+
+func (s *unsafeSortedSet[T]) IsDisjoint(other Collection[T]) bool {
+	ret := true
+	s.Each(func(elem T) bool {
+		ret = !contains(other, elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *unsafeSortedSet[T]) Min() (T, bool) {
+	if s.root == nil {
+		var zero T
+		return zero, false
+	}
+	return minNode(s.root).value, true
+}
+
+func (s *unsafeSortedSet[T]) Max() (T, bool) {
+	if s.root == nil {
+		var zero T
+		return zero, false
+	}
+	return maxNode(s.root).value, true
+}
+
+// search returns the closest element to v on the side picked by the flags:
+// below looks for elements less than v, and inclusive also accepts v itself.
+func (s *unsafeSortedSet[T]) search(v T, below, inclusive bool) (T, bool) {
+	var best *sortedNode[T]
+	for h := s.root; h != nil; {
+		switch c := s.compare(v, h.value); {
+		case c == 0 && inclusive:
+			return h.value, true
+		case c > 0:
+			if below {
+				best = h
+			}
+			h = h.right
+		case c < 0:
+			if !below {
+				best = h
+			}
+			h = h.left
+		case below:
+			h = h.left
+		default:
+			h = h.right
+		}
+	}
+
+	if best == nil {
+		var zero T
+		return zero, false
+	}
+	return best.value, true
+}
+
+func (s *unsafeSortedSet[T]) Floor(elem T) (T, bool) {
+	return s.search(elem, true, true)
+}
+
+func (s *unsafeSortedSet[T]) Ceiling(elem T) (T, bool) {
+	return s.search(elem, false, true)
+}
+
+func (s *unsafeSortedSet[T]) Lower(elem T) (T, bool) {
+	return s.search(elem, true, false)
+}
+
+func (s *unsafeSortedSet[T]) Higher(elem T) (T, bool) {
+	return s.search(elem, false, false)
+}
+
+// sortedRange bounds a walk over a sorted set. A missing bound is open.
+type sortedRange[T any] struct {
+	lo, hi                   T
+	hasLo, hasHi             bool
+	inclusiveLo, inclusiveHi bool
+}
+
+func (s *unsafeSortedSet[T]) inRange(r sortedRange[T], v T) bool {
+	if r.hasLo {
+		if c := s.compare(v, r.lo); c < 0 || (c == 0 && !r.inclusiveLo) {
+			return false
+		}
+	}
+	if r.hasHi {
+		if c := s.compare(v, r.hi); c > 0 || (c == 0 && !r.inclusiveHi) {
+			return false
+		}
+	}
+	return true
+}
+
+// walkRange is like walk, but only visits the values in r and skips the
+// subtrees that lie outside it.
+func (s *unsafeSortedSet[T]) walkRange(h *sortedNode[T], r sortedRange[T], fn func(elem T) bool) bool {
+	if h == nil {
+		return true
+	}
+
+	if (!r.hasLo || s.compare(r.lo, h.value) < 0) && !s.walkRange(h.left, r, fn) {
+		return false
+	}
+	if s.inRange(r, h.value) && !fn(h.value) {
+		return false
+	}
+	if !r.hasHi || s.compare(h.value, r.hi) < 0 {
+		return s.walkRange(h.right, r, fn)
+	}
+	return true
+}
+
+func (s *unsafeSortedSet[T]) rangeSlice(r sortedRange[T]) []T {
+	var keys []T
+	s.walkRange(s.root, r, func(elem T) bool {
+		keys = append(keys, elem)
+		return true
+	})
+	return keys
+}
+
+func (s *unsafeSortedSet[T]) rangeSet(r sortedRange[T]) *unsafeSortedSet[T] {
+	ret := newUnsafeSortedSet(s.compare)
+	ret.Add(s.rangeSlice(r)...)
+	return ret
+}
+
+func (s *unsafeSortedSet[T]) SubSet(lo, hi T, inclusiveLo, inclusiveHi bool) TypedSortedSet[T] {
+	return s.rangeSet(sortedRange[T]{lo: lo, hi: hi, hasLo: true, hasHi: true, inclusiveLo: inclusiveLo, inclusiveHi: inclusiveHi})
+}
+
+func (s *unsafeSortedSet[T]) HeadSet(hi T, inclusive bool) TypedSortedSet[T] {
+	return s.rangeSet(sortedRange[T]{hi: hi, hasHi: true, inclusiveHi: inclusive})
+}
+
+func (s *unsafeSortedSet[T]) TailSet(lo T, inclusive bool) TypedSortedSet[T] {
+	return s.rangeSet(sortedRange[T]{lo: lo, hasLo: true, inclusiveLo: inclusive})
+}
+
+func (s *unsafeSortedSet[T]) RangeIterator(lo, hi T, inclusiveLo, inclusiveHi bool) *Iterator[T] {
+	return newIterator(s.rangeSlice(sortedRange[T]{lo: lo, hi: hi, hasLo: true, hasHi: true, inclusiveLo: inclusiveLo, inclusiveHi: inclusiveHi}))
+}
+
+func (s *unsafeSortedSet[T]) Rank(elem T) int {
+	r := 0
+	for h := s.root; h != nil; {
+		switch c := s.compare(elem, h.value); {
+		case c < 0:
+			h = h.left
+		case c > 0:
+			r += size(h.left) + 1
+			h = h.right
+		default:
+			return r + size(h.left)
+		}
+	}
+	return r
+}
+
+func (s *unsafeSortedSet[T]) Select(k int) (T, bool) {
+	if k < 0 || k >= s.Len() {
+		var zero T
+		return zero, false
+	}
+
+	h := s.root
+	for {
+		switch l := size(h.left); {
+		case k < l:
+			h = h.left
+		case k > l:
+			k -= l + 1
+			h = h.right
+		default:
+			return h.value, true
+		}
+	}
+}
+
+func (s *unsafeSortedSet[T]) CountRange(lo, hi T) int {
+	if s.compare(lo, hi) >= 0 {
+		return 0
+	}
+	return s.Rank(hi) - s.Rank(lo)
+}
@@ -0,0 +1,187 @@
+package set
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ErrUnhashable is returned by TryAdd and TryContains when an element cannot
+// be used as a map key, such as a slice, a map, a func, or a struct or
+// interface holding one.
+type ErrUnhashable struct {
+	Type reflect.Type
+}
+
+func (e ErrUnhashable) Error() string {
+	return fmt.Sprintf("set: unhashable element type %v", e.Type)
+}
+
+// checkHashable returns an ErrUnhashable for the first element of elems that
+// would panic as a map key.
+func checkHashable[T any](elems []T) error {
+	for _, elem := range elems {
+		v := reflect.ValueOf(any(elem))
+		if v.IsValid() && !v.Comparable() {
+			return ErrUnhashable{Type: v.Type()}
+		}
+	}
+	return nil
+}
+
+// hashable filters elems in place down to the elements that can be map
+// keys. The others can never be members of a map backed set.
+func hashable[T any](elems []T) []T {
+	ret := elems[:0]
+	for _, elem := range elems {
+		if v := reflect.ValueOf(any(elem)); !v.IsValid() || v.Comparable() {
+			ret = append(ret, elem)
+		}
+	}
+	return ret
+}
+
+// contains is c.Contains(elems...), except that an element c cannot look up,
+// such as a slice given to a map backed set, counts as missing instead of
+// panicking when c has a TryContains method to tell.
+func contains[T any](c Collection[T], elems ...T) bool {
+	if t, ok := c.(interface{ TryContains(i ...T) (bool, error) }); ok {
+		found, err := t.TryContains(elems...)
+		return err == nil && found
+	}
+	return c.Contains(elems...)
+}
+
+func (s *unsafeSet[T]) TryAdd(i ...T) error {
+	if err := checkHashable(i); err != nil {
+		return err
+	}
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	s.Add(i...)
+	return nil
+}
+
+func (s *unsafeSet[T]) TryContains(i ...T) (bool, error) {
+	if err := checkHashable(i); err != nil {
+		return false, err
+	}
+	return s.Contains(i...), nil
+}
+
+func (s *safeSet[T]) TryAdd(i ...T) error {
+	if err := checkHashable(i); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s.frozen {
+		return ErrFrozen
+	}
+	s.s.Add(i...)
+	return nil
+}
+
+func (s *safeSet[T]) TryContains(i ...T) (bool, error) {
+	if err := checkHashable(i); err != nil {
+		return false, err
+	}
+	return s.Contains(i...), nil
+}
+
+func (s *unsafeOrderedSet[T]) TryAdd(i ...T) error {
+	if err := checkHashable(i); err != nil {
+		return err
+	}
+	if s.frozen {
+		return ErrFrozen
+	}
+
+	s.Add(i...)
+	return nil
+}
+
+func (s *unsafeOrderedSet[T]) TryContains(i ...T) (bool, error) {
+	if err := checkHashable(i); err != nil {
+		return false, err
+	}
+	return s.Contains(i...), nil
+}
+
+func (s *safeOrderedSet[T]) TryAdd(i ...T) error {
+	if err := checkHashable(i); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.s.frozen {
+		return ErrFrozen
+	}
+	s.s.Add(i...)
+	return nil
+}
+
+func (s *safeOrderedSet[T]) TryContains(i ...T) (bool, error) {
+	if err := checkHashable(i); err != nil {
+		return false, err
+	}
+	return s.Contains(i...), nil
+}
+
+// Sorted sets and hash sets never use their elements as map keys, so they
+// accept anything their comparator or hash and equality accept.
+
+func (s *unsafeSortedSet[T]) TryAdd(i ...T) error {
+	if s.frozen {
+		return ErrFrozen
+	}
+	s.Add(i...)
+	return nil
+}
+
+func (s *unsafeSortedSet[T]) TryContains(i ...T) (bool, error) {
+	return s.Contains(i...), nil
+}
+
+func (s *safeSortedSet[T]) TryAdd(i ...T) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.s.frozen {
+		return ErrFrozen
+	}
+	s.s.Add(i...)
+	return nil
+}
+
+func (s *safeSortedSet[T]) TryContains(i ...T) (bool, error) {
+	return s.Contains(i...), nil
+}
+
+func (s *unsafeHashSet[T]) TryAdd(i ...T) error {
+	if s.frozen {
+		return ErrFrozen
+	}
+	s.Add(i...)
+	return nil
+}
+
+func (s *unsafeHashSet[T]) TryContains(i ...T) (bool, error) {
+	return s.Contains(i...), nil
+}
+
+func (s *safeHashSet[T]) TryAdd(i ...T) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.s.frozen {
+		return ErrFrozen
+	}
+	s.s.Add(i...)
+	return nil
+}
+
+func (s *safeHashSet[T]) TryContains(i ...T) (bool, error) {
+	return s.Contains(i...), nil
+}
@@ -0,0 +1,119 @@
+package set
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type withSlice struct {
+	name  string
+	value interface{}
+}
+
+func TestTryAddUnhashable(t *testing.T) {
+	for _, name := range []string{"Set", "OrderedSet", "UnsafeSet", "UnsafeOrderedSet", "ShardedSet", "CopyOnWriteSet"} {
+		s := constructors[name](1)
+		for _, elem := range []interface{}{
+			[]int{1},
+			map[string]int{},
+			func() {},
+			withSlice{"a", []string{"b"}},
+			[1]interface{}{[]byte("c")},
+		} {
+			err := s.TryAdd(2, elem)
+			var unhashable ErrUnhashable
+			if !errors.As(err, &unhashable) {
+				t.Fatalf("%v: expected ErrUnhashable for %T, got %v", name, elem, err)
+			}
+			if unhashable.Type != reflect.TypeOf(elem) {
+				t.Errorf("%v: expected the error to name %T, got %v", name, elem, unhashable.Type)
+			}
+
+			if _, err := s.TryContains(1, elem); !errors.As(err, &unhashable) {
+				t.Errorf("%v: expected ErrUnhashable from TryContains, got %v", name, err)
+			}
+		}
+
+		if s.Len() != 1 || s.Contains(2) {
+			t.Errorf("%v: a failed TryAdd should add nothing, got %v", name, s)
+		}
+	}
+}
+
+func TestTryAddHashable(t *testing.T) {
+	for name, c := range constructors {
+		s := c()
+		if err := s.TryAdd(1, "a", nil, withSlice{"b", 2}, [2]int{3, 4}); err != nil {
+			t.Fatalf("%v: unexpected error %v", name, err)
+		}
+
+		ok, err := s.TryContains(1, "a")
+		if err != nil || !ok {
+			t.Errorf("%v: expected (true, nil), got (%v, %v)", name, ok, err)
+		}
+	}
+}
+
+func TestTryAddReleasesLock(t *testing.T) {
+	for _, s := range []Set{NewSet(), NewOrderedSet(), NewShardedSet(4), NewCopyOnWriteSet()} {
+		s.TryAdd([]int{1})
+
+		done := make(chan struct{})
+		go func() {
+			s.Add(1)
+			s.Clear()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("%T is still locked after a failed TryAdd", s)
+		}
+	}
+}
+
+func TestTryAddAcceptedByHashSets(t *testing.T) {
+	for _, name := range []string{"HashSet", "UnsafeHashSet"} {
+		s := constructors[name]()
+		if err := s.TryAdd([]int{1}); err != nil {
+			t.Errorf("%v: unexpected error %v", name, err)
+		}
+		if ok, err := s.TryContains([]int{1}); !ok || err != nil {
+			t.Errorf("%v: expected (true, nil), got (%v, %v)", name, ok, err)
+		}
+	}
+}
+
+func TestTryAddFrozen(t *testing.T) {
+	for name, c := range constructors {
+		s := c(1)
+		freeze(s)
+
+		err := s.TryAdd([]int{1})
+		var unhashable ErrUnhashable
+		switch name {
+		case "HashSet", "UnsafeHashSet", "SortedSet", "UnsafeSortedSet":
+			if err != ErrFrozen {
+				t.Errorf("%v: expected ErrFrozen for a slice, got %v", name, err)
+			}
+		default:
+			if !errors.As(err, &unhashable) {
+				t.Errorf("%v: expected ErrUnhashable to come before ErrFrozen, got %v", name, err)
+			}
+		}
+
+		if err := s.TryAdd(2); err != ErrFrozen {
+			t.Errorf("%v: expected ErrFrozen, got %v", name, err)
+		}
+	}
+}
+
+func TestErrUnhashableMessage(t *testing.T) {
+	err := NewSet().TryAdd([]string{"a"})
+	if err == nil || err.Error() != "set: unhashable element type []string" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
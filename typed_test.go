@@ -0,0 +1,56 @@
+package set
+
+import "testing"
+
+func TestNewTypedSet(t *testing.T) {
+	a := NewTypedSet("a", "b", "a")
+	if a.Len() != 2 {
+		t.Error("NewTypedSet should dedupe its arguments")
+	}
+
+	if !a.Equal(NewTypedSetFromSlice([]string{"b", "a"})) {
+		t.Error("NewTypedSet and NewTypedSetFromSlice should build equal sets")
+	}
+
+	b := NewTypedUnsafeSetFromSlice([]int{1, 2, 3})
+	total := 0
+	for _, i := range b.ToSlice() {
+		total += i
+	}
+
+	if total != 6 {
+		t.Errorf("expected the elements to sum to 6, got %v", total)
+	}
+}
+
+func TestNewTypedOrderedSet(t *testing.T) {
+	for _, a := range []TypedSet[string]{
+		NewTypedOrderedSet("z", "y", "x"),
+		NewTypedUnsafeOrderedSetFromSlice([]string{"z", "y", "x"}),
+	} {
+		var got []string
+		for s := range a.Iter() {
+			got = append(got, s)
+		}
+
+		if len(got) != 3 || got[0] != "z" || got[1] != "y" || got[2] != "x" {
+			t.Errorf("expected insertion order [z y x], got %v", got)
+		}
+
+		c := a.Clone()
+		c.Remove("y")
+		if a.Equal(c) || c.Len() != 2 {
+			t.Error("removing from a clone should not affect the original")
+		}
+	}
+}
+
+func TestUntypedSetIsTypedAny(t *testing.T) {
+	var a TypedSet[interface{}] = NewSet(1, "a")
+	var b Set = NewTypedSet[interface{}]()
+	b.Add("a", 1)
+
+	if !a.Equal(b.Clone()) {
+		t.Error("Set and TypedSet[interface{}] should be interchangeable")
+	}
+}
@@ -3,112 +3,202 @@ package set
 import "sync"
 
 var (
-	sss safeSet
+	sss *safeSet[interface{}]
 	_   Set = sss
 )
 
-var mu = sync.RWMutex{}
+type safeSet[T comparable] struct {
+	s *unsafeSet[T]
+	sync.RWMutex
+	lockOrder
+}
 
-type safeSet unsafeSet
+func newSafeSet[T comparable]() safeSet[T] {
+	return safeSet[T]{s: newUnsafeSet[T]()}
+}
 
-func newSafeSet() safeSet {
-	return safeSet(newUnsafeSet())
+func (s *safeSet[T]) Add(i ...T) {
+	s.Lock()
+	defer s.Unlock()
+	s.s.Add(i...)
 }
 
-func (s safeSet) Add(i ...interface{}) {
-	mu.Lock()
-	unsafeSet(s).Add(i...)
-	mu.Unlock()
+func (s *safeSet[T]) Contains(i ...T) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Contains(i...)
 }
 
-func (s safeSet) Contains(i ...interface{}) bool {
-	mu.RLock()
-	ret := unsafeSet(s).Contains(i...)
-	mu.RUnlock()
-	return ret
+func (s *safeSet[T]) Clear() {
+	s.Lock()
+	defer s.Unlock()
+	checkFrozen(s.s.frozen)
+	s.s = newUnsafeSet[T]()
 }
 
-func (s safeSet) Clear() {
-	mu.Lock()
-	for k := range s {
-		delete(s, k)
-	}
-	mu.Unlock()
+func (s *safeSet[T]) Remove(i T) {
+	s.Lock()
+	defer s.Unlock()
+	s.s.Remove(i)
 }
 
-func (s safeSet) Remove(i interface{}) {
-	mu.Lock()
-	if _, found := s[i]; !found {
-		return
+func (s *safeSet[T]) Len() int {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.Len()
+}
+
+func (s *safeSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *safeSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
+}
+
+func (s *safeSet[T]) Each(fn func(elem T) bool) {
+	s.RLock()
+	defer s.RUnlock()
+	s.s.Each(fn)
+}
+
+func (s *safeSet[T]) Equal(other Collection[T]) bool {
+	o, ok := other.(*safeSet[T])
+	if !ok {
+		elems := toSlice(other)
+		s.RLock()
+		defer s.RUnlock()
+		return len(elems) == s.s.Len() && contains[T](s.s, elems...)
 	}
-	delete(s, i)
-	mu.Unlock()
+
+	unlock := lockPair(s, o, false)
+	defer unlock()
+	return s.s.Equal(o.s)
+}
+
+func (s *safeSet[T]) Clone() TypedSet[T] {
+	s.RLock()
+	defer s.RUnlock()
+	return &safeSet[T]{s: s.s.Clone().(*unsafeSet[T])}
 }
 
-func (s safeSet) Len() int {
-	mu.RLock()
-	defer mu.RUnlock()
-	return len(s)
+func (s *safeSet[T]) ToSlice() []T {
+	s.RLock()
+	defer s.RUnlock()
+	return s.s.ToSlice()
 }
 
-func (s safeSet) Iter() <-chan interface{} {
-	ch := make(chan interface{})
-	go func() {
-		for key := range s {
-			ch <- key
+func (s *safeSet[T]) RemoveFrom(other Collection[T]) {
+	o, ok := other.(*safeSet[T])
+	if !ok {
+		elems := hashable(toSlice(other))
+		s.Lock()
+		defer s.Unlock()
+		checkFrozen(s.s.frozen)
+		for _, elem := range elems {
+			s.s.Remove(elem)
 		}
-		close(ch)
-	}()
+		return
+	}
 
-	return ch
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.RemoveFrom(o.s)
 }
 
-func (s safeSet) Equal(other Set) bool {
-	o := other.(safeSet)
+func (s *safeSet[T]) AddFrom(other Collection[T]) {
+	o, ok := other.(*safeSet[T])
+	if !ok {
+		elems := toSlice(other)
+		s.Lock()
+		defer s.Unlock()
+		s.s.Add(elems...)
+		return
+	}
 
-	mu.RLock()
-	ret := unsafeSet(s).Equal(unsafeSet(o))
-	mu.RUnlock()
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.AddFrom(o.s)
+}
 
-	return ret
+func (s *safeSet[T]) RetainFrom(other Collection[T]) {
+	o, ok := other.(*safeSet[T])
+	if !ok {
+		keep := newUnsafeSet[T]()
+		keep.Add(hashable(toSlice(other))...)
+		s.Lock()
+		defer s.Unlock()
+		s.s.RetainFrom(keep)
+		return
+	}
+
+	unlock := lockPair(s, o, true)
+	defer unlock()
+	s.s.RetainFrom(o.s)
 }
 
-func (s safeSet) Clone() Set {
-	mu.RLock()
-	defer mu.RUnlock()
-	unsafeClone := unsafeSet(s).Clone()
-	ret := safeSet(unsafeClone.(unsafeSet))
+func (s *safeSet[T]) Union(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).Union)
+}
+
+func (s *safeSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).Intersect)
+}
+
+func (s *safeSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).Difference)
+}
 
-	return ret
+func (s *safeSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	return s.combine(other, (*unsafeSet[T]).SymmetricDifference)
 }
 
-func (s safeSet) ToSlice() []interface{} {
-	keys := make([]interface{}, 0, s.Len())
-	mu.RLock()
-	for key := range s {
-		keys = append(keys, key)
+// combine applies op to the unsafe contents of s and other, holding both
+// read locks when other is a safeSet, and to a copy of s otherwise,
+// so that op reads other with s unlocked.
+func (s *safeSet[T]) combine(other Collection[T], op func(*unsafeSet[T], Collection[T]) TypedSet[T]) TypedSet[T] {
+	if o, ok := other.(*safeSet[T]); ok {
+		unlock := lockPair(s, o, false)
+		defer unlock()
+		return &safeSet[T]{s: op(s.s, o.s).(*unsafeSet[T])}
 	}
-	mu.RUnlock()
-	return keys
+
+	snapshot := newUnsafeSet[T]()
+	s.RLock()
+	snapshot.AddFrom(s.s)
+	s.RUnlock()
+	return &safeSet[T]{s: op(snapshot, other).(*unsafeSet[T])}
+}
+
+func (s *safeSet[T]) IsSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsSubset, isSubset[T])
+}
+
+func (s *safeSet[T]) IsSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsSuperset, isSuperset[T])
 }
 
-func (s safeSet) RemoveFrom(other Set) {
-	mu.Lock()
-	o := other.(safeSet)
-	unsafeSet(s).RemoveFrom(unsafeSet(o))
-	mu.Unlock()
+func (s *safeSet[T]) IsProperSubset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsProperSubset, isProperSubset[T])
 }
 
-func (s safeSet) AddFrom(other Set) {
-	mu.Lock()
-	o := other.(safeSet)
-	unsafeSet(s).AddFrom(unsafeSet(o))
-	mu.Unlock()
+func (s *safeSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsProperSuperset, isProperSuperset[T])
 }
 
-func (s safeSet) RetainFrom(other Set) {
-	mu.Lock()
-	o := other.(safeSet)
-	unsafeSet(s).RetainFrom(unsafeSet(o))
-	mu.Unlock()
+func (s *safeSet[T]) IsDisjoint(other Collection[T]) bool {
+	return s.relate(other, (*unsafeSet[T]).IsDisjoint, isDisjoint[T])
+}
+
+// relate applies op to the unsafe contents of s and other, holding both
+// read locks, when other is a safeSet, and otherwise answers with
+// fallback, which reads the two sets one at a time.
+func (s *safeSet[T]) relate(other Collection[T], op func(*unsafeSet[T], Collection[T]) bool, fallback func(s, other Collection[T]) bool) bool {
+	if o, ok := other.(*safeSet[T]); ok {
+		unlock := lockPair(s, o, false)
+		defer unlock()
+		return op(s.s, o.s)
+	}
+
+	return fallback(s, other)
 }
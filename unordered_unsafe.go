@@ -1,73 +1,90 @@
 package set
 
 var (
-	s unsafeSet
+	s *unsafeSet[interface{}]
 	_ Set = s
 )
 
-type unsafeSet map[interface{}]struct{}
+type unsafeSet[T comparable] struct {
+	m      map[T]struct{}
+	frozen bool
+}
 
-func newUnsafeSet() unsafeSet {
-	return make(map[interface{}]struct{})
+func newUnsafeSet[T comparable]() *unsafeSet[T] {
+	return &unsafeSet[T]{m: make(map[T]struct{})}
 }
 
-func (s unsafeSet) Add(i ...interface{}) {
+func (s *unsafeSet[T]) Add(i ...T) {
+	checkFrozen(s.frozen)
 	for _, item := range i {
-		if _, found := s[item]; found {
+		if _, found := s.m[item]; found {
 			continue
 		}
 
-		s[item] = struct{}{}
+		s.m[item] = struct{}{}
 	}
 }
 
-func (s unsafeSet) Contains(i ...interface{}) bool {
+func (s *unsafeSet[T]) Contains(i ...T) bool {
 	for _, item := range i {
-		if _, found := s[item]; !found {
+		if _, found := s.m[item]; !found {
 			return false
 		}
 	}
 	return true
 }
 
-func (s unsafeSet) Clear() {
-	for k := range s {
-		delete(s, k)
+func (s *unsafeSet[T]) Clear() {
+	checkFrozen(s.frozen)
+	for k := range s.m {
+		delete(s.m, k)
 	}
 }
 
-func (s unsafeSet) Remove(i interface{}) {
-	if _, found := s[i]; !found {
+func (s *unsafeSet[T]) Remove(i T) {
+	checkFrozen(s.frozen)
+	if _, found := s.m[i]; !found {
 		return
 	}
 
-	delete(s, i)
+	delete(s.m, i)
 }
 
-func (s unsafeSet) Len() int {
-	return len(s)
+func (s *unsafeSet[T]) Len() int {
+	return len(s.m)
 }
 
-func (s unsafeSet) Iter() <-chan interface{} {
-	ch := make(chan interface{})
-	go func() {
-		for key := range s {
-			ch <- key
-		}
-
-		close(ch)
-	}()
+func (s *unsafeSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
 
-	return ch
+func (s *unsafeSet[T]) Iterator() *Iterator[T] {
+	return newIterator(s.ToSlice())
 }
 
-func (s unsafeSet) Equal(other Set) bool {
-	_ = other.(unsafeSet)
+func (s *unsafeSet[T]) Each(fn func(elem T) bool) {
+	for key := range s.m {
+		if !fn(key) {
+			return
+		}
+	}
+}
 
+func (s *unsafeSet[T]) Equal(other Collection[T]) bool {
 	if s.Len() != other.Len() {
 		return false
 	}
-	for elem := range s {
+
+	if o, ok := other.(*unsafeSet[T]); ok {
+		for elem := range s.m {
+			if _, found := o.m[elem]; !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	for elem := range s.m {
 		if !other.Contains(elem) {
 			return false
 		}
@@ -75,47 +92,153 @@ func (s unsafeSet) Equal(other Set) bool {
 	return true
 }
 
-func (s unsafeSet) Clone() Set {
-	clonedSet := newUnsafeSet()
-	for key := range s {
-		if key != nil {
+func (s *unsafeSet[T]) Clone() TypedSet[T] {
+	clonedSet := newUnsafeSet[T]()
+	for key := range s.m {
+		if any(key) != nil {
 			clonedSet.Add(key)
 		}
 	}
 	return clonedSet
 }
 
-func (s unsafeSet) ToSlice() []interface{} {
-	keys := make([]interface{}, 0, s.Len())
-	for key := range s {
+func (s *unsafeSet[T]) ToSlice() []T {
+	keys := make([]T, 0, s.Len())
+	for key := range s.m {
 		keys = append(keys, key)
 	}
 
 	return keys
 }
 
-func (s unsafeSet) RemoveFrom(other Set) {
-	for elem := range s {
+func (s *unsafeSet[T]) RemoveFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	if o, ok := other.(*unsafeSet[T]); ok {
+		for elem := range o.m {
+			delete(s.m, elem)
+		}
+		return
+	}
+
+	for elem := range s.m {
 		if other.Contains(elem) {
 			s.Remove(elem)
 		}
 	}
 }
 
-func (s unsafeSet) AddFrom(other Set) {
-	o := other.(unsafeSet)
-
-	for elem := range o {
-		if !s.Contains(elem) {
-			s.Add(elem)
+func (s *unsafeSet[T]) AddFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	if o, ok := other.(*unsafeSet[T]); ok {
+		for elem := range o.m {
+			s.m[elem] = struct{}{}
 		}
+		return
 	}
+
+	s.Add(toSlice(other)...)
 }
 
-func (s unsafeSet) RetainFrom(other Set) {
-	for elem := range s {
+func (s *unsafeSet[T]) RetainFrom(other Collection[T]) {
+	checkFrozen(s.frozen)
+	if o, ok := other.(*unsafeSet[T]); ok {
+		for elem := range s.m {
+			if _, found := o.m[elem]; !found {
+				delete(s.m, elem)
+			}
+		}
+		return
+	}
+
+	for elem := range s.m {
 		if !other.Contains(elem) {
 			s.Remove(elem)
 		}
 	}
 }
+
+func (s *unsafeSet[T]) Union(other Collection[T]) TypedSet[T] {
+	ret := &unsafeSet[T]{m: make(map[T]struct{}, len(s.m))}
+	for elem := range s.m {
+		ret.m[elem] = struct{}{}
+	}
+	ret.AddFrom(other)
+	return ret
+}
+
+func (s *unsafeSet[T]) Intersect(other Collection[T]) TypedSet[T] {
+	ret := newUnsafeSet[T]()
+	for elem := range s.m {
+		if other.Contains(elem) {
+			ret.m[elem] = struct{}{}
+		}
+	}
+	return ret
+}
+
+func (s *unsafeSet[T]) Difference(other Collection[T]) TypedSet[T] {
+	ret := newUnsafeSet[T]()
+	for elem := range s.m {
+		if !other.Contains(elem) {
+			ret.m[elem] = struct{}{}
+		}
+	}
+	return ret
+}
+
+func (s *unsafeSet[T]) SymmetricDifference(other Collection[T]) TypedSet[T] {
+	ret := s.Difference(other).(*unsafeSet[T])
+	for _, elem := range toSlice(other) {
+		if _, found := s.m[elem]; !found {
+			ret.m[elem] = struct{}{}
+		}
+	}
+	return ret
+}
+
+func (s *unsafeSet[T]) IsSubset(other Collection[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+
+	for elem := range s.m {
+		if !other.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeSet[T]) IsSuperset(other Collection[T]) bool {
+	if s.Len() < other.Len() {
+		return false
+	}
+
+	if o, ok := other.(*unsafeSet[T]); ok {
+		return o.IsSubset(s)
+	}
+
+	ret := true
+	other.Each(func(elem T) bool {
+		ret = contains[T](s, elem)
+		return ret
+	})
+	return ret
+}
+
+func (s *unsafeSet[T]) IsProperSubset(other Collection[T]) bool {
+	return s.Len() < other.Len() && s.IsSubset(other)
+}
+
+func (s *unsafeSet[T]) IsProperSuperset(other Collection[T]) bool {
+	return s.Len() > other.Len() && s.IsSuperset(other)
+}
+
+func (s *unsafeSet[T]) IsDisjoint(other Collection[T]) bool {
+	for elem := range s.m {
+		if other.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}